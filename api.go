@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const webhookPathPrefix = "/v2/webhook"
+
+// apiValueFlags are the `oura api` flags that take a value; parseArgs passes
+// them and their value through untouched.
+var apiValueFlags = map[string]bool{"--param": true, "-p": true, "--data": true, "-d": true}
+
+// handleAPI implements `oura api`, a raw passthrough for endpoints the CLI
+// does not model yet. Usercollection paths use the OAuth token (refreshing
+// it as needed); /v2/webhook paths use the app credentials.
+func handleAPI(args []string, opts Options) {
+	if opts.Help {
+		printAPIUsage()
+		return
+	}
+
+	args, paginate := cutBoolFlag(args, "paginate")
+	method := "GET"
+	params := url.Values{}
+	var data string
+	var pos []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		name, val, hasEq := strings.Cut(a, "=")
+		switch name {
+		case "--param", "-p", "--data", "-d":
+			if !hasEq {
+				if i+1 >= len(args) {
+					exitErr(fmt.Errorf("flag %q requires a value", name))
+				}
+				val = args[i+1]
+				i++
+			}
+			if name == "--data" || name == "-d" {
+				data = val
+				continue
+			}
+			k, v, ok := strings.Cut(val, "=")
+			if !ok || k == "" {
+				exitErr(fmt.Errorf("invalid --param %q (want key=value)", val))
+			}
+			params.Add(k, v)
+		default:
+			if strings.HasPrefix(a, "-") {
+				exitErr(fmt.Errorf("unknown flag: %s", a))
+			}
+			pos = append(pos, a)
+		}
+	}
+
+	switch len(pos) {
+	case 1:
+	case 2:
+		method = strings.ToUpper(pos[0])
+		pos = pos[1:]
+	default:
+		printAPIUsage()
+		os.Exit(1)
+	}
+
+	path, err := resolveAPIPath(pos[0], params)
+	if err != nil {
+		exitErr(err)
+	}
+
+	body, err := apiRequest(method, path, params, data, paginate || opts.AllPages)
+	if err != nil {
+		exitErr(err)
+	}
+	writeJSON(body)
+}
+
+// resolveAPIPath maps the user-supplied path to an absolute /v2/... path.
+// Bare paths ("/daily_sleep", "sleep/<id>") go under /v2/usercollection. Any
+// query string is moved into params.
+func resolveAPIPath(raw string, params url.Values) (string, error) {
+	if strings.HasPrefix(raw, "http://") || strings.HasPrefix(raw, "https://") {
+		if !strings.HasPrefix(raw, apiHost+"/") {
+			return "", fmt.Errorf("refusing to send credentials to %s (expected %s)", raw, apiHost)
+		}
+		raw = strings.TrimPrefix(raw, apiHost)
+	}
+
+	p, query, _ := strings.Cut(raw, "?")
+	if query != "" {
+		q, err := url.ParseQuery(query)
+		if err != nil {
+			return "", fmt.Errorf("invalid query string %q: %w", query, err)
+		}
+		for k, vs := range q {
+			for _, v := range vs {
+				params.Add(k, v)
+			}
+		}
+	}
+
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if !strings.HasPrefix(p, "/v2/") {
+		p = strings.TrimPrefix(apiBase, apiHost) + p
+	}
+	return p, nil
+}
+
+func apiRequest(method, path string, params url.Values, data string, paginate bool) ([]byte, error) {
+	var payload any
+	if data != "" {
+		raw := []byte(data)
+		if strings.HasPrefix(data, "@") {
+			b, err := os.ReadFile(data[1:])
+			if err != nil {
+				return nil, err
+			}
+			raw = b
+		}
+		if !json.Valid(raw) {
+			return nil, fmt.Errorf("--data is not valid JSON")
+		}
+		payload = json.RawMessage(raw)
+	}
+
+	if path == webhookPathPrefix || strings.HasPrefix(path, webhookPathPrefix+"/") {
+		if len(params) > 0 {
+			return nil, fmt.Errorf("webhook endpoints take no query parameters")
+		}
+		body, _, err := webhookDo(method, strings.TrimPrefix(path, webhookPathPrefix), payload)
+		return body, err
+	}
+
+	// The usercollection API is read-only.
+	if method != "GET" {
+		return nil, fmt.Errorf("method %s not supported for %s (only GET)", method, path)
+	}
+	if payload != nil {
+		return nil, fmt.Errorf("--data is only supported for webhook endpoints")
+	}
+	// A debugging passthrough shows what the API returns now, so it skips
+	// the response cache.
+	ctx := context.Background()
+	if paginate {
+		return mergePages(ctx, apiFetchURL, apiHost+path, params)
+	}
+	return apiFetchURL(ctx, apiHost+path, params)
+}
+
+func printAPIUsage() {
+	fmt.Println(`Usage: oura api [METHOD] <path> [flags]
+
+Send a raw request to the Oura API and print the response body.
+
+Paths:
+  /daily_sleep                  Relative to /v2/usercollection
+  /v2/usercollection/sleep/<id> Absolute API path
+  /v2/webhook/subscription      Webhook endpoints (app credentials)
+
+Flags:
+  -p, --param key=value   Query parameter (repeatable)
+  -d, --data <json|@file> Request body (webhook endpoints only)
+  --paginate              Follow next_token (also --all-pages, bounded by --max-pages)
+
+Values given to --param and --data are sent as-is. Everything after a bare
+-- is also left to api rather than read as a global flag such as --from or
+--format.
+
+Examples:
+  oura api /daily_sleep --param start_date=2026-10-01
+  oura api GET /v2/usercollection/heartrate -p start_datetime=2026-10-01T00:00:00Z --paginate
+  oura api DELETE /v2/webhook/subscription/<id>
+  oura api /daily_activity -- --param start_date=2026-10-01 --param end_date=2026-10-07`)
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache defaults; override with "cache_ttl", "cache_undated_ttl" and
+// "cache_grace_days" in config.json.
+const (
+	defaultCacheTTL        = 5 * time.Minute
+	defaultCacheUndatedTTL = time.Hour
+	// Oura keeps revising a day for a while (late syncs, sleep scored the
+	// next morning), so only days older than this are treated as final.
+	defaultCacheGraceDays = 3
+)
+
+// cacheMode is set from --no-cache / --refresh.
+var cacheMode = struct {
+	Disabled bool // neither read nor write
+	Refresh  bool // skip reads, still write
+}{}
+
+// cacheEntry is one cached GET response. A zero ExpiresAt never expires.
+type cacheEntry struct {
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	ExpiresAt time.Time       `json:"expires_at,omitempty"`
+	Body      json.RawMessage `json:"body"`
+}
+
+func (e cacheEntry) fresh(now time.Time) bool {
+	return e.ExpiresAt.IsZero() || now.Before(e.ExpiresAt)
+}
+
+func getCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(getConfigDir(), "cache")
+	}
+	return filepath.Join(dir, "oura")
+}
+
+// cacheAccount is the Account of the stored token, read once per run.
+var cacheAccount = sync.OnceValue(func() string {
+	token, err := loadToken()
+	if err != nil {
+		return ""
+	}
+	return token.Account
+})
+
+// cachePath keys entries by account as well as URL: the same URL returns
+// another user's data after `oura auth` with a different account.
+func cachePath(fullURL string) string {
+	key := fullURL
+	if account := cacheAccount(); account != "" {
+		key = account + " " + fullURL
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(getCacheDir(), hex.EncodeToString(sum[:])+".json")
+}
+
+func readCacheEntry(path string) (cacheEntry, error) {
+	var e cacheEntry
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(data, &e)
+	return e, err
+}
+
+// cacheLookup returns the cached body for a GET URL (including its query)
+// if there is a fresh entry.
+func cacheLookup(fullURL string) ([]byte, bool) {
+	if cacheMode.Disabled || cacheMode.Refresh {
+		return nil, false
+	}
+	e, err := readCacheEntry(cachePath(fullURL))
+	if err != nil || e.URL != fullURL || !e.fresh(time.Now()) {
+		return nil, false
+	}
+	return e.Body, true
+}
+
+// cacheStore saves a successful response. Failures only cost a refetch, so
+// they are ignored.
+func cacheStore(fullURL string, params url.Values, body []byte) {
+	if cacheMode.Disabled || !json.Valid(body) {
+		return
+	}
+	now := time.Now()
+	e := cacheEntry{URL: fullURL, FetchedAt: now, Body: body}
+	if ttl := cacheTTLFor(params, now); ttl > 0 {
+		e.ExpiresAt = now.Add(ttl)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	os.MkdirAll(getCacheDir(), 0700)
+	path := cachePath(fullURL)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
+
+// cacheTTLFor decides how long a response stays fresh: forever when the
+// requested range ends before the grace window, the short TTL when it
+// reaches into recent days, and the undated TTL for requests with no range
+// (personal_info, ring_configuration, documents by id).
+func cacheTTLFor(params url.Values, now time.Time) time.Duration {
+	end := params.Get("end_date")
+	if end == "" {
+		if t, err := time.Parse(time.RFC3339, params.Get("end_datetime")); err == nil {
+			end = t.In(now.Location()).Format(dateLayout)
+		}
+	}
+	if end == "" {
+		if params.Get("start_date") != "" || params.Get("start_datetime") != "" {
+			// The API defaults end to today.
+			return cacheTTL()
+		}
+		return cacheUndatedTTL()
+	}
+
+	final := now.AddDate(0, 0, -cacheGraceDays()).Format(dateLayout)
+	if end < final {
+		return 0
+	}
+	return cacheTTL()
+}
+
+func cacheTTL() time.Duration {
+	if d, err := time.ParseDuration(config.CacheTTL); err == nil && d > 0 {
+		return d
+	}
+	return defaultCacheTTL
+}
+
+func cacheUndatedTTL() time.Duration {
+	if d, err := time.ParseDuration(config.CacheUndatedTTL); err == nil && d > 0 {
+		return d
+	}
+	return defaultCacheUndatedTTL
+}
+
+func cacheGraceDays() int {
+	if config.CacheGraceDays != nil && *config.CacheGraceDays >= 0 {
+		return *config.CacheGraceDays
+	}
+	return defaultCacheGraceDays
+}
+
+func handleCache(args []string, opts Options) {
+	if opts.Help || len(args) == 0 {
+		printCacheUsage()
+		if !opts.Help {
+			os.Exit(1)
+		}
+		return
+	}
+
+	rest, expiredOnly := cutBoolFlag(args[1:], "expired")
+	if len(rest) != 0 {
+		printCacheUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "stats":
+		if expiredOnly {
+			exitErr(fmt.Errorf("--expired only applies to clear"))
+		}
+		cacheStats(opts)
+	case "clear":
+		cacheClear(expiredOnly)
+	default:
+		printCacheUsage()
+		os.Exit(1)
+	}
+}
+
+type cacheStatsOutput struct {
+	Dir       string         `json:"dir"`
+	Entries   int            `json:"entries"`
+	Bytes     int64          `json:"bytes"`
+	Final     int            `json:"final"`
+	Fresh     int            `json:"fresh"`
+	Expired   int            `json:"expired"`
+	Oldest    *time.Time     `json:"oldest,omitempty"`
+	Newest    *time.Time     `json:"newest,omitempty"`
+	Endpoints map[string]int `json:"endpoints"`
+}
+
+func cacheStats(opts Options) {
+	now := time.Now()
+	st := cacheStatsOutput{Dir: getCacheDir(), Endpoints: map[string]int{}}
+
+	forEachCacheEntry(func(path string, info os.FileInfo, e cacheEntry) {
+		st.Entries++
+		st.Bytes += info.Size()
+		switch {
+		case e.ExpiresAt.IsZero():
+			st.Final++
+		case e.fresh(now):
+			st.Fresh++
+		default:
+			st.Expired++
+		}
+		if st.Oldest == nil || e.FetchedAt.Before(*st.Oldest) {
+			t := e.FetchedAt
+			st.Oldest = &t
+		}
+		if st.Newest == nil || e.FetchedAt.After(*st.Newest) {
+			t := e.FetchedAt
+			st.Newest = &t
+		}
+		if u, err := url.Parse(e.URL); err == nil {
+			st.Endpoints[cacheEndpointName(u.Path)]++
+		}
+	})
+
+	if opts.JSON {
+		writeJSONToStdout(st)
+		return
+	}
+
+	fmt.Println("Response cache")
+	fmt.Println(strings.Repeat("-", 72))
+	fmt.Printf("Location:  %s\n", st.Dir)
+	fmt.Printf("Entries:   %d (%s)\n", st.Entries, formatBytes(st.Bytes))
+	fmt.Printf("Final:     %d (past days, never refetched)\n", st.Final)
+	fmt.Printf("Fresh:     %d\n", st.Fresh)
+	fmt.Printf("Expired:   %d\n", st.Expired)
+	if st.Oldest != nil {
+		fmt.Printf("Oldest:    %s\n", st.Oldest.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("Newest:    %s\n", st.Newest.Local().Format("2006-01-02 15:04"))
+	}
+	if len(st.Endpoints) > 0 {
+		fmt.Println()
+		names := make([]string, 0, len(st.Endpoints))
+		for n := range st.Endpoints {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Printf("  %-26s %d\n", n, st.Endpoints[n])
+		}
+	}
+}
+
+func cacheClear(expiredOnly bool) {
+	now := time.Now()
+	removed := 0
+	forEachCacheEntry(func(path string, info os.FileInfo, e cacheEntry) {
+		if expiredOnly && e.fresh(now) {
+			return
+		}
+		if os.Remove(path) == nil {
+			removed++
+		}
+	})
+	fmt.Printf("Removed %d cached responses\n", removed)
+}
+
+// forEachCacheEntry calls fn for every readable entry in the cache dir.
+func forEachCacheEntry(fn func(path string, info os.FileInfo, e cacheEntry)) {
+	entries, err := os.ReadDir(getCacheDir())
+	if err != nil {
+		return
+	}
+	for _, de := range entries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(getCacheDir(), de.Name())
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		e, err := readCacheEntry(path)
+		if err != nil {
+			continue
+		}
+		fn(path, info, e)
+	}
+}
+
+// cacheEndpointName turns /v2/usercollection/sleep/<id> into "sleep".
+func cacheEndpointName(path string) string {
+	for _, prefix := range []string{"/v2/sandbox/usercollection/", "/v2/usercollection/"} {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			name, _, _ := strings.Cut(rest, "/")
+			if strings.HasPrefix(prefix, "/v2/sandbox") {
+				return "sandbox/" + name
+			}
+			return name
+		}
+	}
+	return path
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+func printCacheUsage() {
+	fmt.Println(`Usage: oura cache <stats|clear> [--expired] [--json|-j]
+
+API responses are cached on disk (in the user cache dir, e.g. ~/.cache/oura),
+separately for each account logged in with oura auth.
+Ranges ending more than cache_grace_days (default 3) ago are kept for good;
+recent ranges expire after cache_ttl (default 5m) and requests without a
+date range after cache_undated_ttl (default 1h).
+
+Subcommands:
+  stats            Show cache size, freshness and entries per endpoint
+  clear            Remove all cached responses
+  clear --expired  Remove only expired responses
+
+Global flags:
+  --no-cache       Neither read nor write the cache
+  --refresh        Ignore cached responses and refetch (updates the cache)`)
+}
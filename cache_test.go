@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCacheTTLFor(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	q := func(kv ...string) url.Values {
+		v := url.Values{}
+		for i := 0; i < len(kv); i += 2 {
+			v.Set(kv[i], kv[i+1])
+		}
+		return v
+	}
+
+	tests := []struct {
+		name   string
+		params url.Values
+		want   time.Duration
+	}{
+		{"old range is final", q("start_date", "2026-09-01", "end_date", "2026-09-30"), 0},
+		{"last final day", q("start_date", "2026-10-10", "end_date", "2026-10-10"), 0},
+		{"first day in grace window", q("start_date", "2026-10-11", "end_date", "2026-10-11"), defaultCacheTTL},
+		{"today", q("start_date", "2026-10-14", "end_date", "2026-10-14"), defaultCacheTTL},
+		{"range into today", q("start_date", "2026-09-01", "end_date", "2026-10-14"), defaultCacheTTL},
+		{"open-ended range", q("start_date", "2026-09-01"), defaultCacheTTL},
+		{"old datetime range", q("start_datetime", "2026-09-01T00:00:00Z", "end_datetime", "2026-09-02T00:00:00Z"), 0},
+		{"recent datetime range", q("start_datetime", "2026-10-14T00:00:00Z", "end_datetime", "2026-10-14T12:00:00Z"), defaultCacheTTL},
+		{"open-ended datetime range", q("start_datetime", "2026-09-01T00:00:00Z"), defaultCacheTTL},
+		{"undated", nil, defaultCacheUndatedTTL},
+	}
+	for _, tt := range tests {
+		if got := cacheTTLFor(tt.params, now); got != tt.want {
+			t.Errorf("%s: cacheTTLFor(%s) = %s, want %s", tt.name, tt.params.Encode(), got, tt.want)
+		}
+	}
+}
+
+func TestCacheTTLForConfig(t *testing.T) {
+	prev := config
+	t.Cleanup(func() { config = prev })
+	grace := 0
+	config.CacheGraceDays = &grace
+	config.CacheTTL = "1m"
+	config.CacheUndatedTTL = "10m"
+
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	yesterday := url.Values{"start_date": {"2026-10-13"}, "end_date": {"2026-10-13"}}
+	today := url.Values{"start_date": {"2026-10-14"}, "end_date": {"2026-10-14"}}
+	if got := cacheTTLFor(yesterday, now); got != 0 {
+		t.Errorf("yesterday with no grace days: TTL %s, want final", got)
+	}
+	if got := cacheTTLFor(today, now); got != time.Minute {
+		t.Errorf("today: TTL %s, want cache_ttl 1m", got)
+	}
+	if got := cacheTTLFor(nil, now); got != 10*time.Minute {
+		t.Errorf("undated: TTL %s, want cache_undated_ttl 10m", got)
+	}
+}
+
+func TestCacheEntryFresh(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		expires time.Time
+		want    bool
+	}{
+		{time.Time{}, true},
+		{now.Add(time.Second), true},
+		{now, false},
+		{now.Add(-time.Minute), false},
+	} {
+		if got := (cacheEntry{ExpiresAt: tt.expires}).fresh(now); got != tt.want {
+			t.Errorf("fresh with ExpiresAt %s = %t, want %t", tt.expires, got, tt.want)
+		}
+	}
+}
+
+func TestCacheKeyedByAccount(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	prev := cacheAccount
+	t.Cleanup(func() { cacheAccount = prev })
+	account := "alice"
+	cacheAccount = func() string { return account }
+
+	const u = "https://api.example.com/v2/usercollection/personal_info"
+	cacheStore(u, nil, []byte(`{"id":"alice"}`))
+	if body, ok := cacheLookup(u); !ok || string(body) != `{"id":"alice"}` {
+		t.Fatalf("alice: lookup = %q, %t", body, ok)
+	}
+
+	account = "bob"
+	if body, ok := cacheLookup(u); ok {
+		t.Errorf("bob was served alice's entry %q", body)
+	}
+	cacheStore(u, nil, []byte(`{"id":"bob"}`))
+
+	account = ""
+	if body, ok := cacheLookup(u); ok {
+		t.Errorf("no account was served %q", body)
+	}
+
+	account = "alice"
+	if body, ok := cacheLookup(u); !ok || string(body) != `{"id":"alice"}` {
+		t.Errorf("alice after bob: lookup = %q, %t", body, ok)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type CardiovascularAgeResponse struct {
+	Data []CardiovascularAgeRecord `json:"data"`
+}
+
+type CardiovascularAgeRecord struct {
+	ID          string `json:"id"`
+	Day         string `json:"day"`
+	VascularAge *int   `json:"vascular_age"`
+}
+
+func fetchCardioAge(startDate string, endDate string) {
+	body, err := apiGetAll("/daily_cardiovascular_age", paddedRangeParams(startDate, endDate, 0, 0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printCardioAgeDays(startDate, endDate, body, personalAge)
+}
+
+// printCardioAgeDays prints startDate..endDate from a
+// /daily_cardiovascular_age response. age is only called when there is data
+// to compare against.
+func printCardioAgeDays(startDate string, endDate string, body []byte, age func() (int, error)) {
+	var data CardiovascularAgeResponse
+	json.Unmarshal(body, &data)
+
+	label := startDate
+	if endDate != startDate {
+		label = startDate + ".." + endDate
+	}
+
+	var found []CardiovascularAgeRecord
+	for _, r := range data.Data {
+		if r.Day >= startDate && r.Day <= endDate && r.VascularAge != nil {
+			found = append(found, r)
+		}
+	}
+	if len(found) == 0 {
+		fmt.Println("No cardiovascular age data for", label)
+		return
+	}
+
+	// Chronological age is optional context; the command still works without it.
+	chronological, ageErr := age()
+
+	fmt.Printf("🫀 Cardiovascular Age - %s\n", label)
+	fmt.Println(strings.Repeat("─", 40))
+	if ageErr == nil {
+		fmt.Printf("Chronological Age: %d\n", chronological)
+		fmt.Println()
+	}
+
+	for _, date := range daysInRange(startDate, endDate) {
+		records := recordsForDay(found, date, func(r CardiovascularAgeRecord) string { return r.Day })
+		if len(records) == 0 {
+			if startDate != endDate {
+				fmt.Printf("%s  no data\n", date)
+			}
+			continue
+		}
+		v := *records[0].VascularAge
+		if ageErr == nil {
+			fmt.Printf("%s  Vascular Age: %d (%+d)\n", date, v, v-chronological)
+		} else {
+			fmt.Printf("%s  Vascular Age: %d\n", date, v)
+		}
+	}
+
+	if len(found) > 1 {
+		first, last := found[0], found[len(found)-1]
+		change := *last.VascularAge - *first.VascularAge
+		trend := "stable"
+		if change < 0 {
+			trend = "improving"
+		} else if change > 0 {
+			trend = "worsening"
+		}
+		fmt.Println()
+		fmt.Printf("Trend: %d → %d (%+d, %s) from %s to %s\n",
+			*first.VascularAge, *last.VascularAge, change, trend, first.Day, last.Day)
+	}
+}
+
+func printCardioAgeRecord(r CardiovascularAgeRecord) {
+	fmt.Printf("🫀 Cardiovascular Age - %s\n", r.Day)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Printf("Vascular Age:  %s\n", formatOptionalInt(r.VascularAge))
+}
+
+func fetchCardioAgeJSON(startDate string, endDate string) {
+	fetchEndpointsJSON("cardio-age", startDate, endDate, startDate, endDate, []string{"/daily_cardiovascular_age", "/personal_info"})
+}
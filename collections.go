@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// collectionDescriptor describes one /v2/usercollection collection: the CLI
+// names it answers to and how its documents are listed and printed.
+type collectionDescriptor struct {
+	// Path is the API collection name, e.g. "daily_sleep".
+	Path string
+	// Aliases are the CLI command names; the first is shown in usage.
+	Aliases []string
+	Title   string
+	// Dated collections accept start_date/end_date filters.
+	Dated bool
+	// View is the usage line of a date-based view sharing the command name
+	// (e.g. `oura sleep [range]`). When set, `list` must be explicit.
+	View string
+	// Extra is appended verbatim to the usage text.
+	Extra string
+
+	list func(endpoint string, params url.Values, opts Options)
+	get  func(endpoint string, opts Options)
+}
+
+func describeCollection[T any](d collectionDescriptor, listPrinter func(MultiDocumentResponse[T]), docPrinter func(T)) collectionDescriptor {
+	d.list = func(endpoint string, params url.Values, opts Options) {
+		listAndPrint[T](endpoint, params, opts, listPrinter)
+	}
+	d.get = func(endpoint string, opts Options) {
+		getAndPrint[T](endpoint, opts, docPrinter)
+	}
+	return d
+}
+
+// Collections from the /v2/usercollection paths in refs/openapi-1.27.json
+// that support list and get-by-document_id.
+var collections = []collectionDescriptor{
+	describeCollection(collectionDescriptor{
+		Path: "tag", Aliases: []string{"tag"}, Title: "Tags", Dated: true,
+	}, printTagList, printTag),
+	describeCollection(collectionDescriptor{
+		Path: "enhanced_tag", Aliases: []string{"enhanced-tag", "enhanced_tag"}, Title: "Enhanced tags", Dated: true,
+	}, printEnhancedTagList, printEnhancedTag),
+	describeCollection(collectionDescriptor{
+		Path: "session", Aliases: []string{"session"}, Title: "Sessions", Dated: true,
+	}, printSessionList, printSession),
+	describeCollection(collectionDescriptor{
+		Path: "sleep", Aliases: []string{"sleep"}, Title: "Sleep periods", Dated: true,
+		View: "oura sleep [date|range] [--hypnogram] [--json|-j]",
+	}, rowPrinter("Sleep periods", func(s SleepRecord) string {
+		return fmt.Sprintf("%s  %s  %-10s %s", s.ID, s.Day, s.Type, formatDuration(s.TotalSleepDuration))
+	}), func(s SleepRecord) {
+		printSleepDay(s.Day, nil, []SleepRecord{s}, false)
+	}),
+	describeCollection(collectionDescriptor{
+		Path: "daily_sleep", Aliases: []string{"daily-sleep", "daily_sleep"}, Title: "Daily sleep", Dated: true,
+	}, rowPrinter("Daily sleep", func(d DailySleepRecord) string {
+		return fmt.Sprintf("%s  %s  score %d", d.ID, d.Day, d.Score)
+	}), func(d DailySleepRecord) {
+		printSleepDay(d.Day, &d, nil, false)
+	}),
+	describeCollection(collectionDescriptor{
+		Path: "daily_activity", Aliases: []string{"daily-activity", "daily_activity", "activity"}, Title: "Daily activity", Dated: true,
+		View: "oura activity [date|range] [--json|-j]",
+	}, rowPrinter("Daily activity", func(a ActivityRecord) string {
+		return fmt.Sprintf("%s  %s  score %d  %d steps", a.ID, a.Day, a.Score, a.Steps)
+	}), printActivity),
+	describeCollection(collectionDescriptor{
+		Path: "daily_readiness", Aliases: []string{"daily-readiness", "daily_readiness", "readiness"}, Title: "Daily readiness", Dated: true,
+		View: "oura readiness [date|range] [--json|-j]",
+	}, rowPrinter("Daily readiness", func(r ReadinessRecord) string {
+		return fmt.Sprintf("%s  %s  score %d", r.ID, r.Day, r.Score)
+	}), printReadiness),
+	describeCollection(collectionDescriptor{
+		Path: "daily_spo2", Aliases: []string{"daily-spo2", "daily_spo2", "spo2"}, Title: "Daily SpO2", Dated: true,
+		View: "oura spo2 [date|range] [--json|-j]",
+	}, rowPrinter("Daily SpO2", func(s SpO2Record) string {
+		return fmt.Sprintf("%s  %s  %.1f%%", s.ID, s.Day, s.SpO2Percentage.Average)
+	}), printSpO2),
+	describeCollection(collectionDescriptor{
+		Path: "daily_stress", Aliases: []string{"daily-stress", "daily_stress", "stress"}, Title: "Daily stress", Dated: true,
+		View: "oura stress [date|range] [--json|-j]",
+	}, rowPrinter("Daily stress", func(s StressRecord) string {
+		return fmt.Sprintf("%s  %s  %s", s.ID, s.Day, firstNonEmpty(s.DaySummary, "-"))
+	}), printStress),
+	describeCollection(collectionDescriptor{
+		Path: "daily_resilience", Aliases: []string{"daily-resilience", "daily_resilience", "resilience"}, Title: "Daily resilience", Dated: true,
+		View: "oura resilience [date|range] [--json|-j]",
+	}, rowPrinter("Daily resilience", func(r ResilienceRecord) string {
+		return fmt.Sprintf("%s  %s  %s", r.ID, r.Day, r.Level)
+	}), printResilience),
+	describeCollection(collectionDescriptor{
+		Path: "workout", Aliases: []string{"workout"}, Title: "Workouts", Dated: true,
+		View: "oura workout [date|range] [--json|-j]",
+	}, rowPrinter("Workouts", func(w WorkoutRecord) string {
+		label := w.Activity
+		if w.Label != nil && *w.Label != "" {
+			label = *w.Label
+		}
+		return fmt.Sprintf("%s  %s  %s", w.ID, w.Day, truncate(label, 40))
+	}), func(w WorkoutRecord) {
+		printWorkoutsDay(w.Day, []WorkoutRecord{w})
+	}),
+	describeCollection(collectionDescriptor{
+		Path: "vO2_max", Aliases: []string{"vo2-max", "vo2_max", "vO2_max", "vo2"}, Title: "VO2 max", Dated: true,
+		View: "oura vo2 [date|range] [--json|-j]",
+	}, rowPrinter("VO2 max", func(v VO2MaxRecord) string {
+		return fmt.Sprintf("%s  %s  %.1f ml/kg/min", v.ID, v.Day, v.VO2Max)
+	}), printVO2Max),
+	describeCollection(collectionDescriptor{
+		Path: "sleep_time", Aliases: []string{"sleep-time", "sleep_time"}, Title: "Bedtime recommendations", Dated: true,
+		View: "oura sleep-time [date|range] [--json|-j]",
+		Extra: `
+The optimal bedtime window is shown as a local clock window in the
+timezone of the day it was computed for.
+`,
+	}, rowPrinter("Bedtime recommendations", func(r SleepTimeRecord) string {
+		return fmt.Sprintf("%s  %s  %s", r.ID, r.Day, firstNonEmpty(r.Recommendation, r.Status, "-"))
+	}), printSleepTime),
+	describeCollection(collectionDescriptor{
+		Path: "rest_mode_period", Aliases: []string{"rest-mode", "rest_mode", "rest_mode_period"}, Title: "Rest mode periods", Dated: true,
+	}, printRestModeList, printRestMode),
+	describeCollection(collectionDescriptor{
+		Path: "ring_configuration", Aliases: []string{"ring", "ring_configuration"}, Title: "Ring configuration",
+		Extra: `  oura ring current [--json|-j]
+  oura ring firmware [--json|-j]
+
+current   The most recently set up configuration
+firmware  Configurations in set-up order, marking firmware changes
+`,
+	}, printRingList, printRing),
+	describeCollection(collectionDescriptor{
+		Path: "daily_cardiovascular_age", Aliases: []string{"cardio-age", "cardio_age", "cardiovascular-age", "daily_cardiovascular_age"}, Title: "Cardiovascular age", Dated: true,
+		View: "oura cardio-age [date|range] [--json|-j]",
+		Extra: `
+Shows vascular age per day, the difference to your chronological age from
+personal info, and the trend across the range.
+`,
+	}, rowPrinter("Cardiovascular age", func(r CardiovascularAgeRecord) string {
+		return fmt.Sprintf("%s  %s  vascular age %s", r.ID, r.Day, formatOptionalInt(r.VascularAge))
+	}), printCardioAgeRecord),
+}
+
+func findCollection(name string) *collectionDescriptor {
+	for i := range collections {
+		for _, a := range collections[i].Aliases {
+			if a == name {
+				return &collections[i]
+			}
+		}
+	}
+	return nil
+}
+
+func printCollectionUsage(c collectionDescriptor) {
+	name := c.Aliases[0]
+	list := "[list]"
+	if c.View != "" {
+		list = "list"
+	}
+	dates := ""
+	if c.Dated {
+		dates = "[--start-date <date>] [--end-date <date>] "
+	}
+
+	fmt.Printf("%s\n\nUsage:\n", c.Title)
+	if c.View != "" {
+		fmt.Printf("  %s\n", c.View)
+	}
+	fmt.Printf("  oura %s %s %s[--next-token <token>] [--max-pages <n>|--all-pages] [--json|-j]\n", name, list, dates)
+	fmt.Printf("  oura %s get <document_id> [--json|-j]\n", name)
+	fmt.Print(c.Extra)
+}
+
+func handleListGet(c collectionDescriptor, args []string, opts Options) {
+	if opts.Help {
+		printCollectionUsage(c)
+		return
+	}
+
+	// Allow: `oura tag --start-date ...` (implicit list).
+	sub := "list"
+	rest := args
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		sub = args[0]
+		rest = args[1:]
+	}
+
+	switch sub {
+	case "list":
+		params, extra, err := parseRangeQueryFlags(rest)
+		if err != nil {
+			exitErr(err)
+		}
+		if len(extra) != 0 {
+			exitErr(fmt.Errorf("unexpected args: %s", strings.Join(extra, " ")))
+		}
+		if !c.Dated && (params.Has("start_date") || params.Has("end_date")) {
+			exitErr(fmt.Errorf("%s does not support --start-date/--end-date", c.Path))
+		}
+		c.list("/"+c.Path, params, opts)
+	case "get":
+		if len(rest) != 1 {
+			exitErr(fmt.Errorf("missing document_id"))
+		}
+		c.get("/"+c.Path+"/"+url.PathEscape(rest[0]), opts)
+	default:
+		printCollectionUsage(c)
+		os.Exit(1)
+	}
+}
+
+// rowPrinter builds a list printer that renders one line per document.
+func rowPrinter[T any](title string, row func(T) string) func(MultiDocumentResponse[T]) {
+	return func(resp MultiDocumentResponse[T]) {
+		if len(resp.Data) == 0 {
+			fmt.Printf("No %s\n", strings.ToLower(title))
+			return
+		}
+		fmt.Printf("%s (%d)\n", title, len(resp.Data))
+		fmt.Println(strings.Repeat("-", 72))
+		for _, d := range resp.Data {
+			fmt.Println(row(d))
+		}
+		printNextToken(resp.NextToken)
+	}
+}
+
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d", *v)
+}
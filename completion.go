@@ -44,26 +44,80 @@ _oura_complete() {
   local cur prev words cword
   _init_completion -n : || return
 
-  local commands="auth personal-info personal_info personal today all sleep activity readiness heartrate hrv stress spo2 resilience vo2 workout tag enhanced-tag enhanced_tag session webhook help completion completions json"
+  local commands="auth personal-info personal_info personal today all sleep sleep-time sleep_time activity readiness heartrate hrv stress spo2 resilience vo2 workout cardio-age cardio_age daily-sleep daily-activity daily-readiness daily-spo2 daily-stress daily-resilience vo2-max tag enhanced-tag enhanced_tag session rest-mode rest_mode ring webhook api sync export cache mock-server schema help completion completions json"
 
   if [[ $cword -eq 1 ]]; then
     COMPREPLY=( $(compgen -W "$commands" -- "$cur") )
     return
   fi
 
+  if [[ $prev == --format ]]; then
+    COMPREPLY=( $(compgen -W "text json csv tsv ndjson" -- "$cur") )
+    return
+  fi
+  if [[ $prev == --template-file ]]; then
+    _filedir
+    return
+  fi
+
   local cmd=${words[1]}
   case "$cmd" in
-    tag|enhanced-tag|enhanced_tag|session)
+    tag|enhanced-tag|enhanced_tag|session|rest-mode|rest_mode|daily-sleep|daily-activity|daily-readiness|daily-spo2|daily-stress|daily-resilience|vo2-max)
       local subs="list get"
       if [[ $cword -eq 2 ]]; then
         COMPREPLY=( $(compgen -W "$subs" -- "$cur") )
         return
       fi
-      COMPREPLY=( $(compgen -W "--start-date --end-date --next-token --json -j --help -h" -- "$cur") )
+      COMPREPLY=( $(compgen -W "--start-date --end-date --next-token --max-pages --all-pages --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
+      return
+      ;;
+    ring)
+      local subs="list get current firmware"
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "$subs" -- "$cur") )
+        return
+      fi
+      COMPREPLY=( $(compgen -W "--next-token --max-pages --all-pages --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
       return
       ;;
     personal-info|personal_info|personal)
-      COMPREPLY=( $(compgen -W "get --json -j --help -h" -- "$cur") )
+      COMPREPLY=( $(compgen -W "get --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
+      return
+      ;;
+    sync)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "status --since" -- "$cur") )
+        return
+      fi
+      COMPREPLY=( $(compgen -W "--since --json -j --help -h" -- "$cur") )
+      return
+      ;;
+    export)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "sqlite" -- "$cur") )
+        return
+      fi
+      COMPREPLY=( $(compgen -f -W "--from --to --force --help -h" -- "$cur") )
+      return
+      ;;
+    cache)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "stats clear" -- "$cur") )
+        return
+      fi
+      COMPREPLY=( $(compgen -W "--expired --json -j --help -h" -- "$cur") )
+      return
+      ;;
+    mock-server)
+      COMPREPLY=( $(compgen -W "--port --fixtures --page-size --days --seed --help -h" -- "$cur") )
+      return
+      ;;
+    schema)
+      COMPREPLY=( $(compgen -W "v2 --help -h" -- "$cur") )
+      return
+      ;;
+    api)
+      COMPREPLY=( $(compgen -W "GET POST PUT PATCH DELETE --param -p --data -d --paginate --all-pages --max-pages --help -h" -- "$cur") )
       return
       ;;
     webhook)
@@ -72,7 +126,31 @@ _oura_complete() {
         COMPREPLY=( $(compgen -W "$subs" -- "$cur") )
         return
       fi
-      COMPREPLY=( $(compgen -W "--callback-url --verification-token --event-type --data-type --json -j --help -h" -- "$cur") )
+      COMPREPLY=( $(compgen -W "--callback-url --verification-token --event-type --data-type --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
+      return
+      ;;
+    sleep)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "list get" -- "$cur") )
+        return
+      fi
+      COMPREPLY=( $(compgen -W "--from --to --hypnogram --start-date --end-date --next-token --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
+      return
+      ;;
+    sleep-time|sleep_time|activity|readiness|stress|spo2|resilience|vo2|workout|cardio-age|cardio_age)
+      if [[ $cword -eq 2 ]]; then
+        COMPREPLY=( $(compgen -W "list get" -- "$cur") )
+        return
+      fi
+      COMPREPLY=( $(compgen -W "--from --to --start-date --end-date --next-token --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
+      return
+      ;;
+    hrv)
+      COMPREPLY=( $(compgen -W "--from --to --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
+      return
+      ;;
+    heartrate)
+      COMPREPLY=( $(compgen -W "--from --to --since --until --detail --interval --max-hr --zones --json -j --format --template --template-file --fields --where --help -h" -- "$cur") )
       return
       ;;
     completion|completions)
@@ -95,6 +173,7 @@ _oura() {
     'today:Today summary'
     'all:All metrics'
     'sleep:Sleep'
+    'sleep-time:Bedtime recommendations'
     'activity:Activity'
     'readiness:Readiness'
     'heartrate:Heart rate'
@@ -104,10 +183,26 @@ _oura() {
     'resilience:Resilience'
     'vo2:VO2 max'
     'workout:Workouts'
+    'cardio-age:Cardiovascular age'
     'tag:Tags'
     'enhanced-tag:Enhanced tags'
     'session:Sessions'
+    'rest-mode:Rest mode periods'
+    'daily-sleep:Daily sleep documents'
+    'daily-activity:Daily activity documents'
+    'daily-readiness:Daily readiness documents'
+    'daily-spo2:Daily SpO2 documents'
+    'daily-stress:Daily stress documents'
+    'daily-resilience:Daily resilience documents'
+    'vo2-max:VO2 max documents'
+    'ring:Ring configuration'
     'webhook:Webhook subscriptions'
+    'api:Raw API request'
+    'sync:Sync into the local store'
+    'export:Export to SQLite'
+    'cache:Response cache'
+    'mock-server:Local stand-in for the Oura API'
+    'schema:JSON Schema of --json=v2 output'
     'help:Help'
     'completion:Shell completion'
     'json:Alias for all --json'
@@ -120,17 +215,57 @@ _oura() {
 
   local cmd=$words[2]
   case $cmd in
-    tag|enhanced-tag|session)
+    tag|enhanced-tag|session|rest-mode|daily-sleep|daily-activity|daily-readiness|daily-spo2|daily-stress|daily-resilience|vo2-max)
       _values 'subcommand' list get
-      _arguments '--start-date[Start date]' '--end-date[End date]' '--next-token[Next token]' '--json[JSON output]' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      _arguments '--start-date[Start date]' '--end-date[End date]' '--next-token[Next token]' '--max-pages[Maximum pages to follow]' '--all-pages[Follow all pages]' '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    ring)
+      _values 'subcommand' list get current firmware
+      _arguments '--next-token[Next token]' '--max-pages[Maximum pages to follow]' '--all-pages[Follow all pages]' '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
       ;;
     personal-info)
       _values 'subcommand' get
-      _arguments '--json[JSON output]' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      _arguments '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    sync)
+      _values 'subcommand' status
+      _arguments '--since[Backfill from date]' '--json[JSON output]' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    export)
+      _values 'format' sqlite
+      _arguments '--from[Range start]' '--to[Range end]' '--force[Replace the output file]' '--help[Help]' '-h[Help]' '*:output file:_files'
+      ;;
+    cache)
+      _values 'subcommand' stats clear
+      _arguments '--expired[Only expired entries]' '--json[JSON output]' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    schema)
+      _values 'version' v2
+      ;;
+    mock-server)
+      _arguments '--port[Listen port]' '--fixtures[Fixture directory]:directory:_files -/' '--page-size[Documents per page]' '--days[Days of generated data]' '--seed[Data seed]' '--help[Help]' '-h[Help]'
+      ;;
+    api)
+      _arguments '*--param[Query parameter key=value]' '*-p[Query parameter key=value]' '--data[Request body (JSON or @file)]' '-d[Request body (JSON or @file)]' '--paginate[Follow next_token]' '--max-pages[Maximum pages to follow]' '--help[Help]' '-h[Help]'
       ;;
     webhook)
       _values 'subcommand' list get create update delete renew types
-      _arguments '--callback-url[Callback URL]' '--verification-token[Verification token]' '--event-type[create|update|delete]' '--data-type[Data type]' '--json[JSON output]' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      _arguments '--callback-url[Callback URL]' '--verification-token[Verification token]' '--event-type[create|update|delete]' '--data-type[Data type]' '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    sleep)
+      _values 'subcommand' list get
+      _arguments '--from[Range start]' '--to[Range end]' '--hypnogram[Stage, HR and HRV timeline]' '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    sleep-time|activity|readiness|stress|spo2|resilience|vo2|workout|cardio-age)
+      _values 'subcommand' list get
+      _arguments '--start-date[Start date]' '--end-date[End date]' '--next-token[Next token]' \
+        '--from[Range start]' '--to[Range end]' '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    hrv)
+      _arguments '--from[Range start]' '--to[Range end]' '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
+      ;;
+    heartrate)
+      _arguments '--from[Range start]' '--to[Range end]' '--since[Window start (HH:MM or datetime)]' '--until[Window end (HH:MM or datetime)]' '--detail[Timeline, sources and zones]' '--interval[Bucket size, e.g. 15m]' '--max-hr[Max heart rate]' '--zones[Zone boundaries in % of max HR]' '--json[JSON output]' '--format[Output format]:format:(text json csv tsv ndjson)' '--template[Go text/template for each record]:template:' '--template-file[File holding the template]:file:_files' '--fields[Fields to print, e.g. day,score]:fields:' '*--where[Filter, e.g. score<70]:condition:' '-j[JSON output]' '--help[Help]' '-h[Help]'
       ;;
     completion)
       _values 'shell' bash zsh fish
@@ -144,27 +279,86 @@ _oura
 const fishCompletionScript = `# fish completion for oura
 complete -c oura -f
 
-set -l cmds auth personal-info today all sleep activity readiness heartrate hrv stress spo2 resilience vo2 workout tag enhanced-tag session webhook help completion json
+set -l cmds auth personal-info today all sleep sleep-time activity readiness heartrate hrv stress spo2 resilience vo2 workout cardio-age daily-sleep daily-activity daily-readiness daily-spo2 daily-stress daily-resilience vo2-max tag enhanced-tag session rest-mode ring webhook api sync export cache mock-server schema help completion json
 complete -c oura -n 'test (count (commandline -opc)) -eq 1' -a "$cmds"
 
 # Common flags
 complete -c oura -l help -s h -d 'Show help'
 complete -c oura -l json -s j -d 'JSON output'
+complete -c oura -l format -x -a 'text json csv tsv ndjson' -d 'Output format'
+complete -c oura -l template -x -d 'Go text/template for each record'
+complete -c oura -l template-file -r -F -d 'File holding the --template'
+complete -c oura -l fields -x -d 'Fields to print, e.g. day,score'
+complete -c oura -l where -x -d 'Filter records, e.g. score<70'
+complete -c oura -l max-pages -d 'Maximum pages to follow'
+complete -c oura -l all-pages -d 'Follow all pages'
+complete -c oura -l sandbox -d 'Use the API sandbox'
+complete -c oura -l no-cache -d 'Bypass the response cache'
+complete -c oura -l refresh -d 'Refetch cached responses'
+complete -c oura -l offline -d 'Use cached data only'
+
+# sync
+complete -c oura -n '__fish_seen_subcommand_from sync' -a 'status'
+complete -c oura -n '__fish_seen_subcommand_from sync' -l since -d 'Backfill from date'
+
+# export
+complete -c oura -n '__fish_seen_subcommand_from export' -a 'sqlite'
+complete -c oura -n '__fish_seen_subcommand_from export' -F
+complete -c oura -n '__fish_seen_subcommand_from export' -l from -d 'Range start'
+complete -c oura -n '__fish_seen_subcommand_from export' -l to -d 'Range end'
+complete -c oura -n '__fish_seen_subcommand_from export' -l force -d 'Replace the output file'
+
+# cache
+complete -c oura -n '__fish_seen_subcommand_from cache' -a 'stats clear'
+complete -c oura -n '__fish_seen_subcommand_from cache' -l expired -d 'Only expired entries'
 
 # completion
 complete -c oura -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'
 
-# tag/enhanced-tag/session
-for c in tag enhanced-tag session
+# usercollection types (list/get)
+for c in tag enhanced-tag session rest-mode sleep sleep-time activity readiness stress spo2 resilience vo2 workout cardio-age daily-sleep daily-activity daily-readiness daily-spo2 daily-stress daily-resilience vo2-max
   complete -c oura -n "__fish_seen_subcommand_from $c" -a 'list get'
   complete -c oura -n "__fish_seen_subcommand_from $c" -l start-date -d 'Start date'
   complete -c oura -n "__fish_seen_subcommand_from $c" -l end-date -d 'End date'
   complete -c oura -n "__fish_seen_subcommand_from $c" -l next-token -d 'Next token'
 end
 
+# metric commands
+for c in sleep sleep-time activity readiness heartrate hrv stress spo2 resilience vo2 workout cardio-age
+  complete -c oura -n "__fish_seen_subcommand_from $c" -l from -d 'Range start'
+  complete -c oura -n "__fish_seen_subcommand_from $c" -l to -d 'Range end'
+end
+complete -c oura -n '__fish_seen_subcommand_from sleep' -l hypnogram -d 'Stage, HR and HRV timeline'
+complete -c oura -n '__fish_seen_subcommand_from heartrate' -l since -d 'Window start (HH:MM or datetime)'
+complete -c oura -n '__fish_seen_subcommand_from heartrate' -l until -d 'Window end (HH:MM or datetime)'
+complete -c oura -n '__fish_seen_subcommand_from heartrate' -l detail -d 'Timeline, sources and zones'
+complete -c oura -n '__fish_seen_subcommand_from heartrate' -l interval -d 'Bucket size, e.g. 15m'
+complete -c oura -n '__fish_seen_subcommand_from heartrate' -l max-hr -d 'Max heart rate'
+complete -c oura -n '__fish_seen_subcommand_from heartrate' -l zones -d 'Zone boundaries in % of max HR'
+
+# ring
+complete -c oura -n '__fish_seen_subcommand_from ring' -a 'list get current firmware'
+complete -c oura -n '__fish_seen_subcommand_from ring' -l next-token -d 'Next token'
+
 # personal-info
 complete -c oura -n '__fish_seen_subcommand_from personal-info' -a 'get'
 
+# api
+complete -c oura -n '__fish_seen_subcommand_from api' -a 'GET POST PUT PATCH DELETE'
+complete -c oura -n '__fish_seen_subcommand_from api' -s p -l param -d 'Query parameter key=value'
+complete -c oura -n '__fish_seen_subcommand_from api' -s d -l data -d 'Request body (JSON or @file)'
+complete -c oura -n '__fish_seen_subcommand_from api' -l paginate -d 'Follow next_token'
+
+# schema
+complete -c oura -n '__fish_seen_subcommand_from schema' -a 'v2'
+
+# mock-server
+complete -c oura -n '__fish_seen_subcommand_from mock-server' -l port -d 'Listen port'
+complete -c oura -n '__fish_seen_subcommand_from mock-server' -l fixtures -d 'Fixture directory' -r
+complete -c oura -n '__fish_seen_subcommand_from mock-server' -l page-size -d 'Documents per page'
+complete -c oura -n '__fish_seen_subcommand_from mock-server' -l days -d 'Days of generated data'
+complete -c oura -n '__fish_seen_subcommand_from mock-server' -l seed -d 'Data seed'
+
 # webhook
 complete -c oura -n '__fish_seen_subcommand_from webhook' -a 'list get create update delete renew types'
 complete -c oura -n '__fish_seen_subcommand_from webhook' -l callback-url -d 'Callback URL'
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	relativeDaysRe = regexp.MustCompile(`^-(\d+)([dw])$`)
+	isoWeekRe      = regexp.MustCompile(`^(\d{4})-[Ww](\d{1,2})$`)
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// resolveDateExpr turns a date expression into an inclusive day range.
+// Supported forms:
+//
+//	YYYY-MM-DD              a literal day
+//	today, yesterday        relative to now
+//	-Nd, -Nw                N days / weeks ago
+//	monday..sunday (mon..)  the most recent such weekday, today included
+//	this-week, last-week    ISO weeks (Monday to Sunday)
+//	this-month, last-month  calendar months
+//	YYYY-Www                an ISO week, e.g. 2026-W41
+//
+// "this-*" ranges are capped at today. Single-day expressions return
+// startDate == endDate.
+func resolveDateExpr(expr string, now time.Time) (startDate string, endDate string, err error) {
+	e := strings.ToLower(strings.TrimSpace(expr))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	day := func(t time.Time) (string, string, error) {
+		s := t.Format(dateLayout)
+		return s, s, nil
+	}
+	span := func(start, end time.Time) (string, string, error) {
+		if end.After(today) && !start.After(today) {
+			end = today
+		}
+		return start.Format(dateLayout), end.Format(dateLayout), nil
+	}
+
+	switch e {
+	case "today":
+		return day(today)
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1))
+	case "this-week":
+		start := isoWeekStart(today)
+		return span(start, start.AddDate(0, 0, 6))
+	case "last-week":
+		start := isoWeekStart(today).AddDate(0, 0, -7)
+		return span(start, start.AddDate(0, 0, 6))
+	case "this-month":
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return span(start, start.AddDate(0, 1, -1))
+	case "last-month":
+		start := time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, today.Location())
+		return span(start, start.AddDate(0, 1, -1))
+	}
+
+	if wd, ok := weekdayNames[e]; ok {
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		return day(today.AddDate(0, 0, -back))
+	}
+
+	if m := relativeDaysRe.FindStringSubmatch(e); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			n *= 7
+		}
+		return day(today.AddDate(0, 0, -n))
+	}
+
+	if m := isoWeekRe.FindStringSubmatch(e); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		start, ok := isoWeekDate(year, week, today.Location())
+		if !ok {
+			return "", "", fmt.Errorf("invalid ISO week: %q", expr)
+		}
+		return span(start, start.AddDate(0, 0, 6))
+	}
+
+	if t, err := time.ParseInLocation(dateLayout, e, today.Location()); err == nil {
+		return day(t)
+	}
+
+	return "", "", fmt.Errorf("invalid date: %q (expected YYYY-MM-DD, today, yesterday, -Nd, -Nw, a weekday, this-week, last-week, this-month, last-month or YYYY-Www)", expr)
+}
+
+// isoWeekStart returns the Monday of the ISO week containing t.
+func isoWeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// isoWeekDate returns the Monday of ISO week `week` in `year`.
+func isoWeekDate(year int, week int, loc *time.Location) (time.Time, bool) {
+	if week < 1 || week > 53 {
+		return time.Time{}, false
+	}
+	// January 4th is always in ISO week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	start := isoWeekStart(jan4).AddDate(0, 0, (week-1)*7)
+	if y, w := start.ISOWeek(); y != year || w != week {
+		return time.Time{}, false
+	}
+	return start, true
+}
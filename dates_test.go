@@ -0,0 +1,120 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveDateExpr(t *testing.T) {
+	// Wednesday of ISO week 42.
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	newYear := time.Date(2027, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr       string
+		now        time.Time
+		start, end string
+	}{
+		{"2026-03-05", now, "2026-03-05", "2026-03-05"},
+		{"today", now, "2026-10-14", "2026-10-14"},
+		{" Today ", now, "2026-10-14", "2026-10-14"},
+		{"yesterday", now, "2026-10-13", "2026-10-13"},
+		{"yesterday", newYear, "2026-12-31", "2026-12-31"},
+		{"-0d", now, "2026-10-14", "2026-10-14"},
+		{"-0w", now, "2026-10-14", "2026-10-14"},
+		{"-10d", now, "2026-10-04", "2026-10-04"},
+		{"-2w", now, "2026-09-30", "2026-09-30"},
+		{"-3d", newYear, "2026-12-29", "2026-12-29"},
+		{"monday", now, "2026-10-12", "2026-10-12"},
+		{"wed", now, "2026-10-14", "2026-10-14"},
+		{"thursday", now, "2026-10-08", "2026-10-08"},
+		{"this-week", now, "2026-10-12", "2026-10-14"},
+		{"last-week", now, "2026-10-05", "2026-10-11"},
+		{"this-week", newYear, "2026-12-28", "2027-01-01"},
+		{"last-week", newYear, "2026-12-21", "2026-12-27"},
+		{"this-month", now, "2026-10-01", "2026-10-14"},
+		{"last-month", now, "2026-09-01", "2026-09-30"},
+		{"last-month", newYear, "2026-12-01", "2026-12-31"},
+		{"last-month", time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), "2024-02-01", "2024-02-29"},
+		{"2026-W42", now, "2026-10-12", "2026-10-14"},
+		{"2026-w41", now, "2026-10-05", "2026-10-11"},
+		{"2026-W1", now, "2025-12-29", "2026-01-04"},
+		{"2021-W01", now, "2021-01-04", "2021-01-10"},
+		{"2020-W53", now, "2020-12-28", "2021-01-03"},
+		{"2026-W53", now, "2026-12-28", "2027-01-03"},
+	}
+	for _, tt := range tests {
+		start, end, err := resolveDateExpr(tt.expr, tt.now)
+		if err != nil {
+			t.Errorf("resolveDateExpr(%q, %s): %v", tt.expr, tt.now.Format(dateLayout), err)
+			continue
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("resolveDateExpr(%q, %s) = %s..%s, want %s..%s", tt.expr, tt.now.Format(dateLayout), start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestResolveDateExprInvalid(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+	for _, expr := range []string{
+		"",
+		"tomorrow",
+		"next-week",
+		"+3d",
+		"-d",
+		"-3m",
+		"2026-13-01",
+		"2026-02-30",
+		"2026-W00",
+		"2026-W54",
+		"2025-W53",
+		"14/10/2026",
+	} {
+		if start, end, err := resolveDateExpr(expr, now); err == nil {
+			t.Errorf("resolveDateExpr(%q) = %s..%s, want error", expr, start, end)
+		}
+	}
+}
+
+func TestISOWeekStart(t *testing.T) {
+	tests := []struct {
+		day, want string
+	}{
+		{"2026-10-12", "2026-10-12"}, // Monday
+		{"2026-10-14", "2026-10-12"},
+		{"2026-10-18", "2026-10-12"}, // Sunday
+		{"2027-01-01", "2026-12-28"}, // week 53 crosses the year
+		{"2021-01-03", "2020-12-28"},
+		{"2025-01-01", "2024-12-30"},
+		{"2024-02-29", "2024-02-26"},
+	}
+	for _, tt := range tests {
+		day, err := time.Parse(dateLayout, tt.day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := isoWeekStart(day)
+		if got.Format(dateLayout) != tt.want {
+			t.Errorf("isoWeekStart(%s) = %s, want %s", tt.day, got.Format(dateLayout), tt.want)
+		}
+		if got.Weekday() != time.Monday {
+			t.Errorf("isoWeekStart(%s) is a %s", tt.day, got.Weekday())
+		}
+	}
+}
+
+func TestParseDateRangeArgExtraArgs(t *testing.T) {
+	for _, args := range [][]string{
+		{"2026-10-10", "garbage"},
+		{"-7d", "today"},
+	} {
+		if start, end, err := parseDateRangeArg(args, Options{}); err == nil {
+			t.Errorf("parseDateRangeArg(%q) = %s..%s, want error", args, start, end)
+		}
+	}
+	start, end, err := parseDateRangeArg([]string{"2026-10-01..2026-10-07"}, Options{})
+	if err != nil || start != "2026-10-01" || end != "2026-10-07" {
+		t.Errorf("parseDateRangeArg(range) = %s..%s, %v", start, end, err)
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// jsonVersion is set from --json=v2. Version 1 (plain --json) passes the
+// API responses through per endpoint; version 2 merges them per day.
+var jsonVersion int
+
+// daysSchemaVersion is the schema_version of --json=v2 output. Bump it only
+// for changes that break consumers; adding fields isn't one.
+const daysSchemaVersion = 2
+
+// DaySummary is one day across collections, as `oura all` shows it. It is
+// what --template renders for `today` and `all`, and one entry of
+// --json=v2 output; collections with nothing for the day are nil or empty.
+type DaySummary struct {
+	Day               string                   `json:"day"`
+	Readiness         *ReadinessRecord         `json:"readiness,omitempty"`
+	DailySleep        *DailySleepRecord        `json:"daily_sleep,omitempty"`
+	Sleep             []SleepRecord            `json:"sleep,omitempty"`
+	SleepTime         *SleepTimeRecord         `json:"sleep_time,omitempty"`
+	Activity          *ActivityRecord          `json:"activity,omitempty"`
+	Stress            *StressRecord            `json:"stress,omitempty"`
+	SpO2              *SpO2Record              `json:"spo2,omitempty"`
+	Resilience        *ResilienceRecord        `json:"resilience,omitempty"`
+	VO2Max            *VO2MaxRecord            `json:"vo2_max,omitempty"`
+	CardiovascularAge *CardiovascularAgeRecord `json:"cardiovascular_age,omitempty"`
+	Workouts          []WorkoutRecord          `json:"workouts,omitempty"`
+	HeartRate         []HeartRateRecord        `json:"heart_rate,omitempty"`
+}
+
+// MainSleep returns the day's long sleep period, or its longest one.
+func (d DaySummary) MainSleep() *SleepRecord {
+	var best *SleepRecord
+	for i := range d.Sleep {
+		s := &d.Sleep[i]
+		if s.Type == "long_sleep" {
+			return s
+		}
+		if best == nil || s.TotalSleepDuration > best.TotalSleepDuration {
+			best = s
+		}
+	}
+	return best
+}
+
+// daySummaryEndpoints are the collections `today` and `all` merge.
+var daySummaryEndpoints = []string{
+	"/sleep",
+	"/daily_sleep",
+	"/daily_activity",
+	"/daily_readiness",
+	"/heartrate",
+	"/daily_stress",
+	"/daily_spo2",
+	"/daily_resilience",
+	"/vO2_max",
+	"/workout",
+	"/daily_cardiovascular_age",
+}
+
+// DaysOutput is the --json=v2 document: a command's collections merged into
+// one DaySummary per day of the range, each record matched to its day as
+// the human views do. `oura schema` prints its JSON Schema.
+type DaysOutput struct {
+	SchemaVersion int                   `json:"schema_version"`
+	Command       string                `json:"command"`
+	From          string                `json:"from"`
+	To            string                `json:"to"`
+	Days          []DaySummary          `json:"days"`
+	PersonalInfo  *PersonalInfoResponse `json:"personal_info,omitempty"`
+	// Errors maps collections that couldn't be fetched to the error.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+func newDaysOutput(command, startDate, endDate string) *DaysOutput {
+	out := &DaysOutput{SchemaVersion: daysSchemaVersion, Command: command, From: startDate, To: endDate}
+	for day := startDate; day <= endDate; day = nextDay(day) {
+		out.Days = append(out.Days, DaySummary{Day: day})
+	}
+	return out
+}
+
+// add sorts fetched responses into the days; records for days outside the
+// range (the metric commands query padded ranges) are dropped.
+func (o *DaysOutput) add(reqs []endpointRequest, results []endpointResponse) {
+	index := make(map[string]*DaySummary, len(o.Days))
+	for i := range o.Days {
+		index[o.Days[i].Day] = &o.Days[i]
+	}
+	for i, r := range results {
+		err := r.Err
+		if err == nil {
+			err = o.addEndpoint(index, reqs[i].Endpoint, r.Body)
+		}
+		if err != nil {
+			if o.Errors == nil {
+				o.Errors = make(map[string]string)
+			}
+			o.Errors[strings.TrimPrefix(reqs[i].Endpoint, "/")] = err.Error()
+		}
+	}
+}
+
+func (o *DaysOutput) addEndpoint(index map[string]*DaySummary, endpoint string, body []byte) error {
+	switch endpoint {
+	case "/sleep":
+		return addDayRecords(body, index, func(r SleepRecord) string { return r.Day },
+			func(d *DaySummary, r SleepRecord) { d.Sleep = append(d.Sleep, r) })
+	case "/daily_sleep":
+		return addDayRecords(body, index, func(r DailySleepRecord) string { return r.Day },
+			func(d *DaySummary, r DailySleepRecord) { d.DailySleep = firstRecord(d.DailySleep, r) })
+	case "/sleep_time":
+		return addDayRecords(body, index, func(r SleepTimeRecord) string { return r.Day },
+			func(d *DaySummary, r SleepTimeRecord) { d.SleepTime = firstRecord(d.SleepTime, r) })
+	case "/daily_activity":
+		return addDayRecords(body, index, func(r ActivityRecord) string { return r.Day },
+			func(d *DaySummary, r ActivityRecord) { d.Activity = firstRecord(d.Activity, r) })
+	case "/daily_readiness":
+		return addDayRecords(body, index, func(r ReadinessRecord) string { return r.Day },
+			func(d *DaySummary, r ReadinessRecord) { d.Readiness = firstRecord(d.Readiness, r) })
+	case "/heartrate":
+		// Samples have no day; they belong to the local day they fall on.
+		return addDayRecords(body, index, func(r HeartRateRecord) string { return localDay(r.Timestamp) },
+			func(d *DaySummary, r HeartRateRecord) { d.HeartRate = append(d.HeartRate, r) })
+	case "/daily_stress":
+		return addDayRecords(body, index, func(r StressRecord) string { return r.Day },
+			func(d *DaySummary, r StressRecord) { d.Stress = firstRecord(d.Stress, r) })
+	case "/daily_spo2":
+		return addDayRecords(body, index, func(r SpO2Record) string { return r.Day },
+			func(d *DaySummary, r SpO2Record) { d.SpO2 = firstRecord(d.SpO2, r) })
+	case "/daily_resilience":
+		return addDayRecords(body, index, func(r ResilienceRecord) string { return r.Day },
+			func(d *DaySummary, r ResilienceRecord) { d.Resilience = firstRecord(d.Resilience, r) })
+	case "/vO2_max":
+		return addDayRecords(body, index, func(r VO2MaxRecord) string { return r.Day },
+			func(d *DaySummary, r VO2MaxRecord) { d.VO2Max = firstRecord(d.VO2Max, r) })
+	case "/workout":
+		return addDayRecords(body, index, func(r WorkoutRecord) string { return r.Day },
+			func(d *DaySummary, r WorkoutRecord) { d.Workouts = append(d.Workouts, r) })
+	case "/daily_cardiovascular_age":
+		return addDayRecords(body, index, func(r CardiovascularAgeRecord) string { return r.Day },
+			func(d *DaySummary, r CardiovascularAgeRecord) {
+				d.CardiovascularAge = firstRecord(d.CardiovascularAge, r)
+			})
+	case "/personal_info":
+		var pi PersonalInfoResponse
+		if err := json.Unmarshal(body, &pi); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		o.PersonalInfo = &pi
+	}
+	return nil
+}
+
+func addDayRecords[T any](body []byte, index map[string]*DaySummary, dayOf func(T) string, add func(*DaySummary, T)) error {
+	var resp MultiDocumentResponse[T]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	for _, r := range resp.Data {
+		if d := index[dayOf(r)]; d != nil {
+			add(d, r)
+		}
+	}
+	return nil
+}
+
+// firstRecord keeps the day's first record, as the human views do.
+func firstRecord[T any](cur *T, r T) *T {
+	if cur != nil {
+		return cur
+	}
+	return &r
+}
+
+// localDay is the local date of an RFC 3339 timestamp, or "".
+func localDay(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ""
+	}
+	return t.Local().Format(dateLayout)
+}
+
+// writeDaysJSON prints --json=v2 output. Like --json, failed collections
+// are reported in the document rather than failing the command.
+func writeDaysJSON(command, startDate, endDate string, reqs []endpointRequest, results []endpointResponse) {
+	out := newDaysOutput(command, startDate, endDate)
+	out.add(reqs, results)
+	writeJSONToStdout(out)
+}
+
+// fetchDaySummary fetches date's records from every summary collection.
+// Collections that fail are reported on stderr and left empty; the bool
+// reports whether any did.
+func fetchDaySummary(date string) (DaySummary, bool) {
+	queryStart, queryEnd := paddedDateRange(date, 1, 1)
+	reqs := endpointRequests(date, date, queryStart, queryEnd, daySummaryEndpoints)
+	out := newDaysOutput("all", date, date)
+	out.add(reqs, fetchConcurrently(reqs))
+	for _, ep := range reqs {
+		name := strings.TrimPrefix(ep.Endpoint, "/")
+		if err, ok := out.Errors[name]; ok {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %s\n", name, err)
+		}
+	}
+	return out.Days[0], len(out.Errors) > 0
+}
+
+// daysOutputCommand reports whether cmd has --json=v2 output: the metric
+// commands, which merge their collections per day.
+func daysOutputCommand(cmd string) bool {
+	switch cmd {
+	case "today", "all", "sleep", "activity", "readiness", "heartrate", "hrv", "stress", "spo2",
+		"resilience", "vo2", "workout", "sleep-time", "sleep_time", "cardio-age", "cardio_age", "cardiovascular-age":
+		return true
+	}
+	return false
+}
+
+// daysSchema is the JSON Schema of --json=v2 output, derived from the
+// models. schema/v2.schema.json is a copy; TestSchemaFile keeps it current.
+func daysSchema() map[string]any {
+	s := jsonSchema(reflect.TypeFor[DaysOutput]())
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "oura --json=v2 output"
+	s["description"] = "A command's collections merged into one object per day of the requested range. " +
+		"Collections with no data for a day are absent from it; collections that failed are listed in errors. " +
+		"Durations are seconds and timestamps RFC 3339, as the Oura API reports them."
+	props := s["properties"].(map[string]any)
+	props["schema_version"] = map[string]any{"const": daysSchemaVersion}
+	return s
+}
+
+// jsonSchema describes how encoding/json writes a value of type t. Fields
+// are required unless omitempty; pointers and any may be null.
+func jsonSchema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := jsonSchema(t.Elem())
+		if typ, ok := s["type"].(string); ok {
+			s["type"] = []string{typ, "null"}
+		}
+		return s
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		// encoding/json writes nil slices as null.
+		return map[string]any{"type": []string{"array", "null"}, "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		props := map[string]any{}
+		required := []string{}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name := jsonFieldName(f)
+			if name == "" {
+				continue
+			}
+			omitempty := strings.Contains(f.Tag.Get("json"), ",omitempty")
+			fs := jsonSchema(f.Type)
+			if omitempty && (f.Type.Kind() == reflect.Pointer || f.Type.Kind() == reflect.Slice) {
+				// Left out rather than null.
+				fs["type"] = fs["type"].([]string)[0]
+			}
+			if name == "day" && f.Type.Kind() == reflect.String {
+				fs["format"] = "date"
+			}
+			props[name] = fs
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{"type": "object", "properties": props, "required": required}
+	}
+	// any: whatever the API sent.
+	return map[string]any{}
+}
+
+func handleSchema(args []string, opts Options) {
+	if opts.Help || len(args) > 1 || (len(args) == 1 && args[0] != "v2") {
+		printSchemaUsage()
+		if !opts.Help {
+			os.Exit(1)
+		}
+		return
+	}
+	writeJSONToStdout(daysSchema())
+}
+
+func printSchemaUsage() {
+	fmt.Println(`Usage: oura schema [v2]
+
+Print the JSON Schema of --json=v2 output (also in schema/v2.schema.json).`)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestSchemaFile fails when a model changes without regenerating the
+// checked-in schema.
+func TestSchemaFile(t *testing.T) {
+	var want bytes.Buffer
+	enc := json.NewEncoder(&want)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(daysSchema()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("schema/v2.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Error("schema/v2.schema.json is out of date; regenerate it with: go run . schema v2 > schema/v2.schema.json")
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// exportTable maps one API collection to an SQLite table (see sqlite.go).
+type exportTable struct {
+	Name     string
+	Endpoint string
+	// Key is the primary key for models without an id.
+	Key      []string
+	Children []exportChild
+	Ref      *exportRef
+}
+
+var exportTables = []exportTable{
+	{Name: "daily_sleep", Endpoint: "/daily_sleep", Children: []exportChild{
+		{Table: "sleep_contributors", Parent: "daily_sleep_id", Fields: []string{"contributors"}},
+	}},
+	{Name: "sleep_periods", Endpoint: "/sleep", Children: []exportChild{
+		{Table: "sleep_period_samples", Parent: "sleep_period_id", Fields: []string{"heart_rate", "hrv"}},
+	}, Ref: &exportRef{Column: "daily_sleep_id", Table: "daily_sleep", index: refByDay}},
+	{Name: "daily_readiness", Endpoint: "/daily_readiness", Children: []exportChild{
+		{Table: "readiness_contributors", Parent: "daily_readiness_id", Fields: []string{"contributors"}},
+	}},
+	{Name: "daily_activity", Endpoint: "/daily_activity"},
+	{Name: "heartrate_samples", Endpoint: "/heartrate", Key: []string{"timestamp", "source"},
+		Ref: &exportRef{Column: "sleep_period_id", Table: "sleep_periods", index: refSleepPeriod}},
+	{Name: "workout", Endpoint: "/workout"},
+	{Name: "tag", Endpoint: "/tag", Children: []exportChild{
+		{Table: "tag_tags", Parent: "tag_id", Fields: []string{"tags"}},
+	}},
+	{Name: "enhanced_tag", Endpoint: "/enhanced_tag"},
+	{Name: "session", Endpoint: "/session", Children: []exportChild{
+		{Table: "session_samples", Parent: "session_id", Fields: []string{"heart_rate", "heart_rate_variability", "motion_count"}},
+	}},
+	{Name: "daily_spo2", Endpoint: "/daily_spo2"},
+	{Name: "daily_stress", Endpoint: "/daily_stress"},
+	{Name: "daily_resilience", Endpoint: "/daily_resilience", Children: []exportChild{
+		{Table: "resilience_contributors", Parent: "daily_resilience_id", Fields: []string{"contributors"}},
+	}},
+	{Name: "vo2_max", Endpoint: "/vO2_max"},
+	{Name: "daily_cardiovascular_age", Endpoint: "/daily_cardiovascular_age"},
+}
+
+// refByDay links a document to the target document of the same day.
+func refByDay(targets []reflect.Value) func(reflect.Value) any {
+	ids := map[string]string{}
+	for _, d := range targets {
+		day, _ := recordDay(d)
+		ids[day] = d.Interface().(DailySleepRecord).ID
+	}
+	return func(doc reflect.Value) any {
+		day, _ := recordDay(doc)
+		if id, ok := ids[day]; ok {
+			return id
+		}
+		return nil
+	}
+}
+
+// refSleepPeriod links a heart rate sample to the sleep period it was
+// recorded in.
+func refSleepPeriod(targets []reflect.Value) func(reflect.Value) any {
+	type period struct {
+		id         string
+		start, end time.Time
+	}
+	var periods []period
+	for _, d := range targets {
+		p := d.Interface().(SleepRecord)
+		start, err1 := time.Parse(time.RFC3339, p.BedtimeStart)
+		end, err2 := time.Parse(time.RFC3339, p.BedtimeEnd)
+		if err1 == nil && err2 == nil {
+			periods = append(periods, period{p.ID, start, end})
+		}
+	}
+	return func(doc reflect.Value) any {
+		t, err := time.Parse(time.RFC3339, doc.Interface().(HeartRateRecord).Timestamp)
+		if err != nil {
+			return nil
+		}
+		for _, p := range periods {
+			if !t.Before(p.start) && t.Before(p.end) {
+				return p.id
+			}
+		}
+		return nil
+	}
+}
+
+func handleExport(args []string, opts Options) {
+	if opts.Help || len(args) == 0 {
+		printExportUsage()
+		if !opts.Help {
+			os.Exit(1)
+		}
+		return
+	}
+	if args[0] != "sqlite" {
+		exitErr(fmt.Errorf("unknown export format %q (supported: sqlite)", args[0]))
+	}
+
+	rest, force := cutBoolFlag(args[1:], "force")
+	if len(rest) == 0 || len(rest) > 2 || strings.HasPrefix(rest[0], "-") {
+		printExportUsage()
+		os.Exit(1)
+	}
+	out := rest[0]
+
+	var startDate, endDate string
+	if len(rest) == 1 && opts.From == "" && opts.To == "" {
+		now := time.Now()
+		startDate = now.AddDate(0, 0, -(defaultSyncDays - 1)).Format(dateLayout)
+		endDate = now.Format(dateLayout)
+	} else {
+		var err error
+		startDate, endDate, err = parseDateRangeArg(rest[1:], opts)
+		if err != nil {
+			exitErr(err)
+		}
+	}
+
+	if _, err := os.Stat(out); err == nil && !force {
+		exitErr(fmt.Errorf("%s already exists (use --force to replace it)", out))
+	}
+
+	// An export is a complete copy of the range, not a capped first page.
+	maxPages = 0
+	tables, failed := fetchExportTables(startDate, endDate)
+
+	// Build the database beside out and move it into place once complete;
+	// a leftover from an interrupted export would already hold the tables.
+	tmp := out + ".tmp"
+	os.Remove(tmp)
+	if err := writeSQLite(tmp, tables); err != nil {
+		os.Remove(tmp)
+		exitErr(err)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		os.Remove(tmp)
+		exitErr(err)
+	}
+
+	fmt.Printf("Exported %s..%s to %s\n", startDate, endDate, out)
+	fmt.Println(strings.Repeat("-", 72))
+	for _, t := range tables {
+		fmt.Printf("%-26s %6d rows\n", t.Name, len(t.Docs))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// fetchExportTables fetches every export table for the date range. A table
+// whose endpoint fails is still created, empty, and reported on stderr.
+func fetchExportTables(startDate, endDate string) ([]exportData, bool) {
+	var reqs []endpointRequest
+	var owner []int
+	for i, t := range exportTables {
+		if t.Endpoint == "/heartrate" {
+			// Heart-rate windows are limited, so fetch it a week at a time.
+			for from := startDate; from <= endDate; {
+				f, _ := time.ParseInLocation(dateLayout, from, time.Local)
+				to := min(f.AddDate(0, 0, syncHeartRateChunkDays-1).Format(dateLayout), endDate)
+				reqs = append(reqs, endpointRequest{Endpoint: t.Endpoint, Params: heartRateParams(dayWindow(from, to))})
+				owner = append(owner, i)
+				from = nextDay(to)
+			}
+			continue
+		}
+		params := url.Values{}
+		params.Set("start_date", startDate)
+		params.Set("end_date", endDate)
+		reqs = append(reqs, endpointRequest{Endpoint: t.Endpoint, Params: params})
+		owner = append(owner, i)
+	}
+
+	tables := make([]exportData, len(exportTables))
+	for i, t := range exportTables {
+		tables[i] = exportData{exportTable: t}
+	}
+	errs := make([]error, len(exportTables))
+	for i, r := range fetchConcurrently(reqs) {
+		ti := owner[i]
+		if errs[ti] != nil {
+			continue
+		}
+		err := r.Err
+		if err == nil {
+			var docs []reflect.Value
+			docs, err = endpointModels[exportTables[ti].Endpoint].decode(r.Body, false)
+			tables[ti].Docs = append(tables[ti].Docs, docs...)
+		}
+		if err != nil {
+			errs[ti] = err
+			tables[ti].Docs = nil
+		}
+	}
+
+	failed := false
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %v (table left empty)\n", exportTables[i].Name, err)
+			failed = true
+		}
+	}
+	return tables, failed
+}
+
+func printExportUsage() {
+	fmt.Println(`Usage: oura export sqlite <out.db> [date|range] [--from <d>] [--to <d>] [--force]
+
+Write the date range (default: the last 30 days) to a new SQLite database
+with one table per data type, keyed by document id:
+
+  daily_sleep, sleep_periods, daily_readiness, daily_activity,
+  heartrate_samples, workout, tag, enhanced_tag, session, daily_spo2,
+  daily_stress, daily_resilience, vo2_max, daily_cardiovascular_age
+
+Columns follow the API fields, with nested objects flattened by name (e.g.
+spo2_percentage_average, readiness_score). Contributors, sample series and
+tag lists go to child tables referencing their document:
+
+  sleep_contributors, readiness_contributors, resilience_contributors
+  sleep_period_samples, session_samples (series, timestamp, value)
+  tag_tags (position, value)
+
+sleep_periods.daily_sleep_id links a period to its day's daily_sleep, and
+heartrate_samples.sleep_period_id a sample to the sleep period it falls in.
+A table whose data could not be fetched is created empty and reported.
+
+Flags:
+  --force          Replace <out.db> if it exists`)
+}
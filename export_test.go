@@ -0,0 +1,95 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func queryInt(t *testing.T, db *sql.DB, query string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestMockExportSQLite(t *testing.T) {
+	c := newMockCLI(t, 10)
+	out := filepath.Join(t.TempDir(), "oura.db")
+	c.run("export", "sqlite", out, "last-week")
+
+	db, err := sql.Open("sqlite", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if rows, err := db.Query("PRAGMA foreign_key_check"); err != nil {
+		t.Fatal(err)
+	} else {
+		if rows.Next() {
+			t.Error("foreign_key_check reports violations")
+		}
+		rows.Close()
+	}
+
+	for _, et := range exportTables {
+		if n := queryInt(t, db, "SELECT count(*) FROM pragma_table_info('"+et.Name+"') WHERE pk > 0"); n != len(et.key()) {
+			t.Errorf("%s: %d primary key columns, want %d", et.Name, n, len(et.key()))
+		}
+		for _, ch := range et.Children {
+			q := "SELECT count(*) FROM pragma_foreign_key_list('" + ch.Table + "') WHERE \"table\" = '" + et.Name + "' AND \"from\" = '" + ch.Parent + "'"
+			if n := queryInt(t, db, q); n != 1 {
+				t.Errorf("%s: no foreign key %s to %s", ch.Table, ch.Parent, et.Name)
+			}
+		}
+	}
+
+	periods := queryInt(t, db, "SELECT count(*) FROM sleep_periods")
+	for _, tt := range []struct {
+		query string
+		want  int
+	}{
+		{"SELECT count(*) FROM daily_readiness", 7},
+		{"SELECT count(*) FROM readiness_contributors", 7 * len(recordColumns(reflect.TypeFor[ReadinessContributors]()))},
+		{"SELECT count(*) FROM sleep_periods WHERE daily_sleep_id IS NULL", 0},
+		{"SELECT count(DISTINCT sleep_period_id) FROM sleep_period_samples WHERE series = 'heart_rate'", periods},
+	} {
+		if got := queryInt(t, db, tt.query); got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.query, got, tt.want)
+		}
+	}
+
+	// Heart rate recorded during a sleep period links to it.
+	linked := queryInt(t, db, `SELECT count(*) FROM heartrate_samples h JOIN sleep_periods p ON p.id = h.sleep_period_id
+		WHERE julianday(h.timestamp) BETWEEN julianday(p.bedtime_start) AND julianday(p.bedtime_end)`)
+	if linked == 0 || linked != queryInt(t, db, "SELECT count(sleep_period_id) FROM heartrate_samples") {
+		t.Errorf("%d heart rate samples linked to their sleep period", linked)
+	}
+}
+
+func TestSampleRows(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+	rows, err := sampleRows("hrv", SampleModel{
+		Interval:  300,
+		Items:     []*float64{v(40), nil, v(42.5)},
+		Timestamp: "2026-10-13T23:00:00.000+02:00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]any{
+		{"hrv", "2026-10-13T23:00:00+02:00", 40.0},
+		{"hrv", "2026-10-13T23:10:00+02:00", 42.5},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("sampleRows = %v, want %v", rows, want)
+	}
+
+	if _, err := sampleRows("hrv", SampleModel{Items: []*float64{v(1)}, Timestamp: "yesterday"}); err == nil {
+		t.Error("sampleRows accepted an invalid timestamp")
+	}
+}
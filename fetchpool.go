@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// fetchWorkers bounds how many API requests fetchConcurrently has in flight.
+	fetchWorkers = 4
+	// fetchDeadline is shared by all requests of one fetchConcurrently call.
+	fetchDeadline = 2 * time.Minute
+)
+
+// endpointRequest is one usercollection fetch. Collections are fetched with
+// apiGetAll; Single endpoints (personal_info) with a plain GET.
+type endpointRequest struct {
+	Endpoint string
+	Params   url.Values
+	Single   bool
+}
+
+type endpointResponse struct {
+	Body []byte
+	Err  error
+}
+
+// fetchConcurrently fetches reqs on a bounded worker pool under a shared
+// deadline. Results are returned in request order.
+func fetchConcurrently(reqs []endpointRequest) []endpointResponse {
+	results := make([]endpointResponse, len(reqs))
+	err := runFetchPool(len(reqs), func(ctx context.Context, i int) {
+		r := reqs[i]
+		fullURL := endpointURL(r.Endpoint)
+		if r.Single {
+			results[i].Body, results[i].Err = apiGetURLContext(ctx, fullURL, r.Params)
+		} else {
+			results[i].Body, results[i].Err = apiGetAllURLContext(ctx, fullURL, r.Params)
+		}
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+	}
+	return results
+}
+
+// streamConcurrently fetches reqs like fetchConcurrently but hands each page
+// of documents to emit as it arrives instead of buffering whole responses.
+// Calls to emit are serialised; a Single request's document is one page.
+// It returns each request's error.
+func streamConcurrently(reqs []endpointRequest, emit func(i int, docs []json.RawMessage) error) []error {
+	errs := make([]error, len(reqs))
+	var mu sync.Mutex
+	err := runFetchPool(len(reqs), func(ctx context.Context, i int) {
+		r := reqs[i]
+		fullURL := endpointURL(r.Endpoint)
+		if r.Single {
+			body, err := apiGetURLContext(ctx, fullURL, r.Params)
+			if err == nil {
+				mu.Lock()
+				err = emit(i, []json.RawMessage{body})
+				mu.Unlock()
+			}
+			errs[i] = err
+			return
+		}
+		errs[i] = apiGetPagesContext(ctx, fullURL, r.Params, func(page MultiDocumentResponse[json.RawMessage]) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return emit(i, page.Data)
+		})
+	})
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+// runFetchPool calls fetch for 0..n-1 on fetchWorkers goroutines under a
+// shared fetchDeadline. It fails early only if no token can be had.
+func runFetchPool(n int, fetch func(ctx context.Context, i int)) error {
+	// Resolve (and if needed refresh) the token once, before the workers
+	// race for it. Offline runs read the cache and need none.
+	if !offline {
+		if _, err := getValidToken(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchDeadline)
+	defer cancel()
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(fetchWorkers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fetch(ctx, i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return nil
+}
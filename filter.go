@@ -0,0 +1,572 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// recordFilter is --fields and --where: which records to print and which of
+// their fields. Both work on the flattened record columns (see records.go),
+// so paths are JSON names joined with ".", e.g. contributors.hrv_balance.
+type recordFilter struct {
+	Fields []string
+	Where  []whereCond
+}
+
+// whereCond is one --where comparison, e.g. score<70.
+type whereCond struct {
+	Field string
+	Op    string
+	Value string
+}
+
+var outputFilter recordFilter
+
+func filteredOutput() bool {
+	return len(outputFilter.Fields) > 0 || len(outputFilter.Where) > 0
+}
+
+// whereOps are the --where operators, longest first so "<=" isn't read as "<".
+var whereOps = []string{"<=", ">=", "!=", "==", "=", "<", ">"}
+
+// parseFields splits a --fields list.
+func parseFields(v string) ([]string, error) {
+	var fields []string
+	for _, f := range strings.Split(v, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("--fields needs at least one field")
+	}
+	return fields, nil
+}
+
+// parseWhere parses a --where comparison: <field><op><value>.
+func parseWhere(v string) (whereCond, error) {
+	i := strings.IndexAny(v, "<>=!")
+	if i <= 0 {
+		return whereCond{}, fmt.Errorf("invalid --where %q (e.g. score<70, day>=2026-10-01)", v)
+	}
+	for _, op := range whereOps {
+		if strings.HasPrefix(v[i:], op) {
+			c := whereCond{
+				Field: strings.TrimSpace(v[:i]),
+				Op:    op,
+				Value: strings.TrimSpace(v[i+len(op):]),
+			}
+			if c.Op == "==" {
+				c.Op = "="
+			}
+			if c.Field == "" || c.Value == "" {
+				return whereCond{}, fmt.Errorf("invalid --where %q (e.g. score<70, day>=2026-10-01)", v)
+			}
+			return c, nil
+		}
+	}
+	return whereCond{}, fmt.Errorf("invalid --where %q (operators: %s)", v, strings.Join(whereOps, " "))
+}
+
+// match compares one flattened value. Numbers compare numerically, text
+// (days, timestamps, types) lexically; a missing value never matches.
+func (c whereCond) match(v any) bool {
+	var cmp int
+	switch v := v.(type) {
+	case nil:
+		return false
+	case int64, float64:
+		want, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			// Booleans are flattened to 0/1.
+			switch c.Value {
+			case "true":
+				want = 1
+			case "false":
+				want = 0
+			default:
+				return false
+			}
+		}
+		got := float64(0)
+		if n, ok := v.(int64); ok {
+			got = float64(n)
+		} else {
+			got = v.(float64)
+		}
+		switch {
+		case got < want:
+			cmp = -1
+		case got > want:
+			cmp = 1
+		}
+	case string:
+		cmp = strings.Compare(v, c.Value)
+	default:
+		return false
+	}
+	switch c.Op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "!=":
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+// fits reports whether the value can be compared with a column of kind k:
+// numbers only compare with numbers (and booleans, flattened to 0/1, also
+// with true/false). An unknown kind (Invalid) accepts anything.
+func (c whereCond) fits(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool:
+		if c.Value == "true" || c.Value == "false" {
+			return true
+		}
+		fallthrough
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		_, err := strconv.ParseFloat(c.Value, 64)
+		return err == nil
+	}
+	return true
+}
+
+// selects reports whether field selects column name. A field naming a
+// nested object (contributors) selects all of its columns.
+func (f recordFilter) selects(field, name string) bool {
+	return name == field || strings.HasPrefix(name, field+".")
+}
+
+// check rejects fields that none of the models printed by a command have,
+// and --where values that can't be compared with the field.
+func (f recordFilter) check(types ...reflect.Type) error {
+	var names []string
+	var kinds []reflect.Kind
+	for _, t := range types {
+		for _, c := range recordColumns(t) {
+			names = append(names, c.Name("."))
+			kinds = append(kinds, c.Kind)
+		}
+	}
+	return f.checkColumns(names, kinds)
+}
+
+// checkColumns is check for columns by name and kind.
+func (f recordFilter) checkColumns(names []string, kinds []reflect.Kind) error {
+	has := func(field string, exact bool) bool {
+		for _, n := range names {
+			if n == field || (!exact && f.selects(field, n)) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, field := range f.Fields {
+		if !has(field, false) {
+			return fmt.Errorf("unknown field %q", field)
+		}
+	}
+	for _, c := range f.Where {
+		if !has(c.Field, true) {
+			return fmt.Errorf("unknown --where field %q", c.Field)
+		}
+		fits := false
+		for i, n := range names {
+			if n == c.Field && c.fits(kinds[i]) {
+				fits = true
+				break
+			}
+		}
+		if !fits {
+			return fmt.Errorf("invalid --where %s%s%s: %s is a number", c.Field, c.Op, c.Value, c.Field)
+		}
+	}
+	return nil
+}
+
+// checkFilter exits on --fields/--where paths that no model has.
+func checkFilter(types ...reflect.Type) {
+	if !filteredOutput() {
+		return
+	}
+	if err := outputFilter.check(types...); err != nil {
+		exitErr(err)
+	}
+}
+
+func checkFilterFor[T any]() {
+	checkFilter(reflect.TypeFor[T]())
+}
+
+// checkEndpointsFilter is checkFilter for the models behind reqs.
+func checkEndpointsFilter(reqs []endpointRequest) {
+	var types []reflect.Type
+	for _, r := range reqs {
+		types = append(types, endpointModels[r.Endpoint].Type)
+	}
+	checkFilter(types...)
+}
+
+// matchValue reports whether a record passes every --where.
+func (f recordFilter) matchValue(v reflect.Value) bool {
+	var names []string
+	for _, c := range recordColumns(v.Type()) {
+		names = append(names, c.Name("."))
+	}
+	return f.matches(names, recordValues(v))
+}
+
+// matches reports whether a flattened record passes every --where. Rows of
+// merged tables may be shorter than names; missing cells are missing values.
+func (f recordFilter) matches(names []string, values []any) bool {
+	for _, c := range f.Where {
+		found := false
+		for i, n := range names {
+			if n == c.Field && i < len(values) {
+				if !c.match(values[i]) {
+					return false
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// filterDoc applies the filter to one decoded record. With --fields the
+// result holds just those fields, nested as in the API; otherwise it is raw,
+// the record as received (or v re-encoded when raw is nil). Records of a
+// model that has none of the fields are dropped along with non-matches.
+func (f recordFilter) filterDoc(v reflect.Value, raw json.RawMessage) (json.RawMessage, bool) {
+	cols := recordColumns(v.Type())
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.Name(".")
+	}
+	values := recordValues(v)
+	if !f.matches(names, values) {
+		return nil, false
+	}
+	if len(f.Fields) == 0 {
+		if raw == nil {
+			b, err := json.Marshal(v.Interface())
+			if err != nil {
+				return nil, false
+			}
+			raw = b
+		}
+		return raw, true
+	}
+
+	out := map[string]any{}
+	selected := false
+	for _, field := range f.Fields {
+		for i, c := range cols {
+			if !f.selects(field, names[i]) {
+				continue
+			}
+			selected = true
+			m := out
+			for _, p := range c.Path[:len(c.Path)-1] {
+				next, ok := m[p].(map[string]any)
+				if !ok {
+					next = map[string]any{}
+					m[p] = next
+				}
+				m = next
+			}
+			m[c.Path[len(c.Path)-1]] = jsonValue(c, values[i])
+		}
+	}
+	if !selected {
+		return nil, false
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// jsonValue turns a flattened value back into its JSON form.
+func jsonValue(c recordColumn, v any) any {
+	switch c.Kind {
+	case reflect.Bool:
+		if n, ok := v.(int64); ok {
+			return n != 0
+		}
+	case reflect.Slice, reflect.Map, reflect.Interface:
+		if s, ok := v.(string); ok {
+			return json.RawMessage(s)
+		}
+	}
+	return v
+}
+
+// filterBody applies the filter to an endpoint response decoded with m:
+// the document of a single-document response (null when it doesn't match),
+// or the data of a list, keeping the rest of the envelope. Records with a
+// day outside startDate..endDate are dropped unless the range is empty.
+func filterBody(m recordModel, body []byte, single bool, startDate, endDate string) ([]byte, error) {
+	keep := func(raw json.RawMessage) (json.RawMessage, bool, error) {
+		v, err := m.decodeDoc(raw)
+		if err != nil {
+			return nil, false, err
+		}
+		if day, ok := recordDay(v); ok && startDate != "" && (day < startDate || day > endDate) {
+			return nil, false, nil
+		}
+		out, ok := outputFilter.filterDoc(v, raw)
+		return out, ok, nil
+	}
+
+	if single {
+		out, ok, err := keep(body)
+		if err != nil || !ok {
+			return []byte("null"), err
+		}
+		return out, nil
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	var docs []json.RawMessage
+	if err := json.Unmarshal(envelope["data"], &docs); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	data := []json.RawMessage{}
+	for _, d := range docs {
+		out, ok, err := keep(d)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			data = append(data, out)
+		}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	envelope["data"] = b
+	return json.Marshal(envelope)
+}
+
+// filterValues applies the filter to decoded records, for JSON output of
+// responses that aren't endpoint envelopes.
+func filterValues[T any](docs []T) []json.RawMessage {
+	checkFilterFor[T]()
+	out := []json.RawMessage{}
+	for i := range docs {
+		if b, ok := outputFilter.filterDoc(reflect.ValueOf(docs[i]), nil); ok {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// filterTable drops the rows that don't match and, with --fields, the
+// columns that weren't asked for. A leading collection column is kept, and
+// rows with none of the fields (another collection's) are dropped.
+func (f recordFilter) filterTable(t *recordTable) error {
+	if err := f.checkColumns(t.Columns, t.kinds()); err != nil {
+		return err
+	}
+
+	rows := t.Rows[:0]
+	for _, r := range t.Rows {
+		if f.matches(t.Columns, r) {
+			rows = append(rows, r)
+		}
+	}
+	t.Rows = rows
+	if len(f.Fields) == 0 {
+		return nil
+	}
+
+	var keep []int
+	merged := len(t.Columns) > 0 && t.Columns[0] == "collection"
+	if merged {
+		keep = append(keep, 0)
+	}
+	for _, field := range f.Fields {
+		for i, c := range t.Columns {
+			if f.selects(field, c) && !(merged && i == 0) {
+				keep = append(keep, i)
+			}
+		}
+	}
+	cols := make([]string, len(keep))
+	for i, k := range keep {
+		cols[i] = t.Columns[k]
+	}
+	rows = t.Rows[:0]
+	for _, r := range t.Rows {
+		row := make([]any, len(keep))
+		empty := true
+		for i, k := range keep {
+			if k < len(r) {
+				row[i] = r[k]
+			}
+			if row[i] != nil && !(merged && i == 0) {
+				empty = false
+			}
+		}
+		if merged && empty {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	t.Columns, t.Rows = cols, rows
+	return nil
+}
+
+// kinds infers each column's kind from its first value; columns that are
+// empty in every row are Invalid.
+func (t *recordTable) kinds() []reflect.Kind {
+	kinds := make([]reflect.Kind, len(t.Columns))
+	for i := range kinds {
+		for _, r := range t.Rows {
+			if i < len(r) && r[i] != nil {
+				kinds[i] = reflect.TypeOf(r[i]).Kind()
+				break
+			}
+		}
+	}
+	return kinds
+}
+
+// filterDaySummary drops the records of s that don't pass --where, so
+// `today` and `all` templates select from the same per-collection records
+// as every other output. It reports whether any record is left.
+func (f recordFilter) filterDaySummary(s *DaySummary) bool {
+	v := reflect.ValueOf(s).Elem()
+	kept := false
+	for i := range v.NumField() {
+		fv := v.Field(i)
+		switch fv.Kind() {
+		case reflect.Pointer:
+			if fv.IsNil() {
+				continue
+			}
+			if f.matchValue(fv.Elem()) {
+				kept = true
+			} else {
+				fv.SetZero()
+			}
+		case reflect.Slice:
+			out := reflect.Zero(fv.Type())
+			for j := range fv.Len() {
+				if f.matchValue(fv.Index(j)) {
+					out = reflect.Append(out, fv.Index(j))
+				}
+			}
+			kept = kept || out.Len() > 0
+			fv.Set(out)
+		}
+	}
+	return kept
+}
+
+// printRecordTable prints t as aligned text, for --fields/--where without a
+// machine format. A single field prints bare values, one per line; columns
+// empty in every row are left out.
+func printRecordTable(t *recordTable) {
+	if len(t.Columns) == 1 {
+		for _, r := range t.Rows {
+			fmt.Println(textCell(r[0]))
+		}
+		return
+	}
+	if len(t.Rows) == 0 {
+		fmt.Println("No matching records")
+		return
+	}
+	var keep []int
+	for i := range t.Columns {
+		for _, r := range t.Rows {
+			if i < len(r) && r[i] != nil {
+				keep = append(keep, i)
+				break
+			}
+		}
+	}
+	cols := make([]string, len(keep))
+	rows := make([][]any, len(t.Rows))
+	for j, k := range keep {
+		cols[j] = t.Columns[k]
+	}
+	for i, r := range t.Rows {
+		rows[i] = make([]any, len(keep))
+		for j, k := range keep {
+			if k < len(r) {
+				rows[i][j] = r[k]
+			}
+		}
+	}
+	t = &recordTable{Columns: cols, Rows: rows}
+
+	widths := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		widths[i] = len(c)
+	}
+	for _, r := range t.Rows {
+		for i := range widths {
+			if i < len(r) {
+				widths[i] = max(widths[i], len(textCell(r[i])))
+			}
+		}
+	}
+	line := func(cells func(i int) string) {
+		var b strings.Builder
+		for i, w := range widths {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			if i == len(widths)-1 {
+				b.WriteString(cells(i))
+			} else {
+				fmt.Fprintf(&b, "%-*s", w, cells(i))
+			}
+		}
+		fmt.Println(b.String())
+	}
+	line(func(i int) string { return t.Columns[i] })
+	total := 0
+	for _, w := range widths {
+		total += w + 2
+	}
+	fmt.Println(strings.Repeat("-", min(total-2, 72)))
+	for _, r := range t.Rows {
+		line(func(i int) string {
+			if i < len(r) {
+				return textCell(r[i])
+			}
+			return ""
+		})
+	}
+}
+
+func textCell(v any) string {
+	if v == nil {
+		return "-"
+	}
+	return formatCell(v)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseWhere(t *testing.T) {
+	tests := []struct {
+		in   string
+		want whereCond
+	}{
+		{"score<70", whereCond{"score", "<", "70"}},
+		{"score <= 70", whereCond{"score", "<=", "70"}},
+		{"day>=2026-10-01", whereCond{"day", ">=", "2026-10-01"}},
+		{"type==long_sleep", whereCond{"type", "=", "long_sleep"}},
+		{"type=long_sleep", whereCond{"type", "=", "long_sleep"}},
+		{"contributors.hrv_balance!=80", whereCond{"contributors.hrv_balance", "!=", "80"}},
+		{"timestamp>2026-10-01T12:00:00+02:00", whereCond{"timestamp", ">", "2026-10-01T12:00:00+02:00"}},
+	}
+	for _, tt := range tests {
+		got, err := parseWhere(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseWhere(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "score", "<70", "score<", "score< ", " =1"} {
+		if got, err := parseWhere(in); err == nil {
+			t.Errorf("parseWhere(%q) = %+v, want error", in, got)
+		}
+	}
+}
+
+func TestWhereMatch(t *testing.T) {
+	tests := []struct {
+		cond string
+		v    any
+		want bool
+	}{
+		{"score<70", int64(63), true},
+		{"score<70", int64(70), false},
+		{"score<=70", int64(70), true},
+		{"score>70", int64(9), false}, // numerically, not "9" > "70"
+		{"score=70", 70.0, true},
+		{"score!=70", int64(63), true},
+		{"temperature_deviation<-0.1", -0.25, true},
+		{"temperature_deviation>=0.5", 0.25, false},
+		{"enabled=true", int64(1), true}, // booleans are flattened to 0/1
+		{"enabled=false", int64(1), false},
+		{"day>=2026-10-01", "2026-10-14", true},
+		{"day<2026-10-01", "2026-10-14", false},
+		{"type=long_sleep", "long_sleep", true},
+		{"type!=long_sleep", "rest", true},
+		{"score<70", nil, false}, // missing values never match
+		{"score!=70", nil, false},
+	}
+	for _, tt := range tests {
+		c, err := parseWhere(tt.cond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.match(tt.v); got != tt.want {
+			t.Errorf("%s with %v = %t, want %t", tt.cond, tt.v, got, tt.want)
+		}
+	}
+}
+
+func whereFilter(t *testing.T, fields string, where ...string) recordFilter {
+	t.Helper()
+	var f recordFilter
+	if fields != "" {
+		var err error
+		if f.Fields, err = parseFields(fields); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, w := range where {
+		c, err := parseWhere(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Where = append(f.Where, c)
+	}
+	return f
+}
+
+func TestFilterCheck(t *testing.T) {
+	readiness := reflect.TypeFor[ReadinessRecord]()
+	sleep := reflect.TypeFor[SleepRecord]()
+	tests := []struct {
+		fields string
+		where  []string
+		ok     bool
+	}{
+		{"day,score", nil, true},
+		{"contributors", nil, true},
+		{"contributors.hrv_balance", nil, true},
+		{"contributors.hrv", nil, false},
+		{"readiness.score", nil, true}, // the sleep period's readiness
+		{"bogus", nil, false},
+		{"", []string{"score<70"}, true},
+		{"", []string{"contributors.hrv_balance>=80"}, true},
+		{"", []string{"contributors<1"}, false}, // objects can't be compared
+		{"", []string{"score<abc"}, false},
+		{"", []string{"score!=abc"}, false},
+		{"", []string{"temperature_deviation>-0.5"}, true},
+		{"", []string{"day>=2026-10-01"}, true},
+		{"", []string{"day<abc"}, true},
+		{"", []string{"bogus=1"}, false},
+	}
+	for _, tt := range tests {
+		err := whereFilter(t, tt.fields, tt.where...).check(readiness, sleep)
+		if (err == nil) != tt.ok {
+			t.Errorf("--fields %q --where %q: err = %v, want ok=%t", tt.fields, tt.where, err, tt.ok)
+		}
+	}
+}
+
+func TestFilterDoc(t *testing.T) {
+	hrv := 70
+	r := ReadinessRecord{ID: "r1", Day: "2026-10-14", Score: 63}
+	r.Contributors.HRVBalance = &hrv
+	r.Contributors.RestingHeartRate = 99
+	v := reflect.ValueOf(r)
+
+	tests := []struct {
+		fields string
+		where  []string
+		want   string // "" when dropped
+	}{
+		{"day,score", nil, `{"day":"2026-10-14","score":63}`},
+		{"contributors.hrv_balance", nil, `{"contributors":{"hrv_balance":70}}`},
+		{"score,contributors.hrv_balance,contributors.resting_heart_rate", nil,
+			`{"contributors":{"hrv_balance":70,"resting_heart_rate":99},"score":63}`},
+		{"contributors.sleep_balance", nil, `{"contributors":{"sleep_balance":null}}`},
+		{"day", []string{"score<70"}, `{"day":"2026-10-14"}`},
+		{"day", []string{"score<70", "contributors.hrv_balance>70"}, ""},
+		{"day", []string{"contributors.sleep_balance>0"}, ""}, // missing value
+		{"readiness.score", nil, ""},                          // no such field in this model
+	}
+	for _, tt := range tests {
+		got, ok := whereFilter(t, tt.fields, tt.where...).filterDoc(v, nil)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("--fields %q --where %q kept %s", tt.fields, tt.where, got)
+			}
+			continue
+		}
+		if !ok || string(got) != tt.want {
+			t.Errorf("--fields %q --where %q = %s, %t; want %s", tt.fields, tt.where, got, ok, tt.want)
+		}
+	}
+
+	raw := json.RawMessage(`{"id":"r1","score":63,"extra":true}`)
+	if got, ok := whereFilter(t, "", "score<70").filterDoc(v, raw); !ok || string(got) != string(raw) {
+		t.Errorf("--where without --fields = %s, %t; want the raw record", got, ok)
+	}
+}
+
+func TestFilterTable(t *testing.T) {
+	table := func() *recordTable {
+		return &recordTable{
+			Columns: []string{"collection", "day", "score", "contributors.deep_sleep", "bpm"},
+			Rows: [][]any{
+				{"daily_sleep", "2026-10-13", int64(81), int64(90)},
+				{"daily_sleep", "2026-10-14", int64(64), int64(70)},
+				{"daily_readiness", "2026-10-14", int64(63)},
+				{"heartrate", nil, nil, nil, int64(55)},
+			},
+		}
+	}
+
+	tab := table()
+	if err := whereFilter(t, "day,contributors", "score<70").filterTable(tab); err != nil {
+		t.Fatal(err)
+	}
+	wantCols := []string{"collection", "day", "contributors.deep_sleep"}
+	wantRows := [][]any{
+		{"daily_sleep", "2026-10-14", int64(70)},
+		{"daily_readiness", "2026-10-14", nil},
+	}
+	if !reflect.DeepEqual(tab.Columns, wantCols) || !reflect.DeepEqual(tab.Rows, wantRows) {
+		t.Errorf("filterTable = %v %v, want %v %v", tab.Columns, tab.Rows, wantCols, wantRows)
+	}
+
+	// Rows of collections without any selected field are dropped.
+	tab = table()
+	if err := whereFilter(t, "bpm").filterTable(tab); err != nil {
+		t.Fatal(err)
+	}
+	if len(tab.Rows) != 1 || tab.Rows[0][1] != int64(55) {
+		t.Errorf("--fields bpm rows = %v", tab.Rows)
+	}
+
+	for _, f := range []recordFilter{
+		whereFilter(t, "nope"),
+		whereFilter(t, "", "nope<1"),
+		whereFilter(t, "", "score<abc"),
+	} {
+		if err := f.filterTable(table()); err == nil {
+			t.Errorf("filterTable with %+v: no error", f)
+		}
+	}
+}
+
+func TestFilterDaySummary(t *testing.T) {
+	s := DaySummary{
+		Day:        "2026-10-14",
+		Readiness:  &ReadinessRecord{Day: "2026-10-14", Score: 63},
+		DailySleep: &DailySleepRecord{Day: "2026-10-14", Score: 81},
+		Sleep:      []SleepRecord{{Day: "2026-10-14", Type: "long_sleep"}, {Day: "2026-10-14", Type: "rest"}},
+		HeartRate:  []HeartRateRecord{{BPM: 55}},
+	}
+
+	got := s
+	if !whereFilter(t, "", "score<70").filterDaySummary(&got) {
+		t.Fatal("score<70 left no records")
+	}
+	if got.Readiness == nil || got.DailySleep != nil || len(got.Sleep) != 0 || len(got.HeartRate) != 0 {
+		t.Errorf("score<70 kept %+v", got)
+	}
+
+	got = s
+	if !whereFilter(t, "", "type=rest").filterDaySummary(&got) || len(got.Sleep) != 1 || got.Sleep[0].Type != "rest" || got.Readiness != nil {
+		t.Errorf("type=rest kept %+v", got)
+	}
+
+	got = s
+	if whereFilter(t, "", "score>90").filterDaySummary(&got) {
+		t.Errorf("score>90 kept %+v", got)
+	}
+}
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestPrintRecordTable(t *testing.T) {
+	out := captureStdout(t, func() {
+		printRecordTable(&recordTable{
+			Columns: []string{"day", "score", "empty"},
+			Rows:    [][]any{{"2026-10-13", int64(81)}, {"2026-10-14", nil, nil}},
+		})
+	})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "day         score" || lines[2] != "2026-10-13  81" || lines[3] != "2026-10-14  -" {
+		t.Errorf("printRecordTable printed:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		printRecordTable(&recordTable{Columns: []string{"score"}, Rows: [][]any{{int64(63)}, {nil}}})
+	})
+	if out != "63\n-\n" {
+		t.Errorf("single column printed %q, want bare values", out)
+	}
+
+	out = captureStdout(t, func() {
+		printRecordTable(&recordTable{Columns: []string{"day", "score"}})
+	})
+	if out != "No matching records\n" {
+		t.Errorf("empty table printed %q", out)
+	}
+}
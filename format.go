@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// outputFormat is set from --format. Machine-readable output (opts.JSON)
+// is JSON unless it names a tabular format. "text" is the aligned table
+// that --fields/--where print without a machine format.
+var outputFormat string
+
+// outputFormats are the values --format accepts.
+var outputFormats = []string{"text", "json", "csv", "tsv", "ndjson"}
+
+func tabularOutput() bool {
+	return outputFormat == "csv" || outputFormat == "tsv" || outputFormat == "text"
+}
+
+// ndjsonOutput is --format ndjson: one normalised record per line, written
+// page by page as responses arrive.
+func ndjsonOutput() bool {
+	return outputFormat == "ndjson"
+}
+
+// recordTable is flattened records ready for CSV/TSV. Column names are JSON
+// paths joined with ".", e.g. contributors.deep_sleep.
+type recordTable struct {
+	Columns []string
+	Rows    [][]any
+}
+
+func newRecordTable(t reflect.Type) *recordTable {
+	var names []string
+	for _, c := range recordColumns(t) {
+		names = append(names, c.Name("."))
+	}
+	return &recordTable{Columns: names}
+}
+
+func (t *recordTable) add(v reflect.Value) {
+	t.Rows = append(t.Rows, recordValues(v))
+}
+
+// merge appends other's rows under a leading "collection" column, adding
+// columns that t doesn't have yet. Commands that combine collections
+// (sleep, hrv, all) print one table with the union of their columns.
+func (t *recordTable) merge(collection string, other *recordTable) {
+	if len(t.Columns) == 0 || t.Columns[0] != "collection" {
+		t.Columns = append([]string{"collection"}, t.Columns...)
+		for i, r := range t.Rows {
+			t.Rows[i] = append([]any{nil}, r...)
+		}
+	}
+	index := make(map[string]int, len(t.Columns))
+	for i, c := range t.Columns {
+		index[c] = i
+	}
+	for _, c := range other.Columns {
+		if _, ok := index[c]; !ok {
+			index[c] = len(t.Columns)
+			t.Columns = append(t.Columns, c)
+		}
+	}
+	for _, r := range other.Rows {
+		row := make([]any, len(t.Columns))
+		row[0] = collection
+		for i, v := range r {
+			row[index[other.Columns[i]]] = v
+		}
+		t.Rows = append(t.Rows, row)
+	}
+}
+
+// keepDays drops rows whose day column is outside startDate..endDate; the
+// metric commands query padded ranges.
+func (t *recordTable) keepDays(startDate, endDate string) {
+	col := -1
+	for i, c := range t.Columns {
+		if c == "day" {
+			col = i
+		}
+	}
+	if col < 0 {
+		return
+	}
+	rows := t.Rows[:0]
+	for _, r := range t.Rows {
+		if day, ok := r[col].(string); ok && (day < startDate || day > endDate) {
+			continue
+		}
+		rows = append(rows, r)
+	}
+	t.Rows = rows
+}
+
+// writeTable prints t to stdout as CSV, TSV or aligned text, after
+// --fields/--where.
+func writeTable(t *recordTable) {
+	if filteredOutput() {
+		if err := outputFilter.filterTable(t); err != nil {
+			exitErr(err)
+		}
+	}
+	if outputFormat == "text" {
+		printRecordTable(t)
+		return
+	}
+	w := csv.NewWriter(os.Stdout)
+	if outputFormat == "tsv" {
+		w.Comma = '\t'
+	}
+	w.Write(t.Columns)
+	cells := make([]string, len(t.Columns))
+	for _, r := range t.Rows {
+		for i := range cells {
+			cells[i] = ""
+			if i < len(r) {
+				cells[i] = formatCell(r[i])
+			}
+		}
+		w.Write(cells)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		exitErr(err)
+	}
+}
+
+func formatCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// writeRecords prints documents of one model as a table.
+func writeRecords[T any](docs []T) {
+	t := newRecordTable(reflect.TypeFor[T]())
+	for i := range docs {
+		t.add(reflect.ValueOf(docs[i]))
+	}
+	writeTable(t)
+}
+
+// endpointTable flattens an endpoint response with the endpoint's model.
+func endpointTable(endpoint string, body []byte, single bool) (*recordTable, error) {
+	m, ok := endpointModels[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("no tabular format for %s", strings.TrimPrefix(endpoint, "/"))
+	}
+	docs, err := m.decode(body, single)
+	if err != nil {
+		return nil, err
+	}
+	t := newRecordTable(m.Type)
+	for _, d := range docs {
+		t.add(d)
+	}
+	return t, nil
+}
+
+// parseOutputFormat validates a --format value.
+func parseOutputFormat(v string) (string, error) {
+	for _, f := range outputFormats {
+		if v == f {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("invalid --format %q (one of: %s)", v, strings.Join(outputFormats, ", "))
+}
+
+// recordOutputCommand reports whether cmd can print CSV, TSV or NDJSON.
+// Commands that don't print API records (sync, cache, export, api, …) can't.
+func recordOutputCommand(cmd string) bool {
+	switch cmd {
+	case "auth", "help", "completion", "completions", "api", "cache", "sync", "export", "mock-server":
+		return false
+	}
+	return true
+}
+
+// recordWriter writes NDJSON lines: each record decoded through its model
+// and re-encoded with a leading "collection" field.
+type recordWriter struct {
+	w *bufio.Writer
+}
+
+var ndjsonOut = recordWriter{w: bufio.NewWriter(os.Stdout)}
+
+func (rw recordWriter) write(collection string, v reflect.Value) error {
+	var b []byte
+	if filteredOutput() {
+		var ok bool
+		if b, ok = outputFilter.filterDoc(v, nil); !ok {
+			return nil
+		}
+	} else {
+		var err error
+		if b, err = json.Marshal(v.Interface()); err != nil {
+			return err
+		}
+	}
+	name, _ := json.Marshal(collection)
+	rw.w.WriteString(`{"collection":`)
+	rw.w.Write(name)
+	if len(b) > 2 {
+		rw.w.WriteByte(',')
+		rw.w.Write(b[1:])
+	} else {
+		rw.w.WriteByte('}')
+	}
+	return rw.w.WriteByte('\n')
+}
+
+// flush ends a page of output.
+func (rw recordWriter) flush() {
+	if err := rw.w.Flush(); err != nil {
+		exitErr(err)
+	}
+}
+
+// writeNDJSON prints documents of one model as NDJSON.
+func writeNDJSON[T any](collection string, docs []T) {
+	checkFilterFor[T]()
+	for i := range docs {
+		if err := ndjsonOut.write(collection, reflect.ValueOf(docs[i])); err != nil {
+			exitErr(err)
+		}
+	}
+	ndjsonOut.flush()
+}
+
+// streamRecords writes a collection listing as NDJSON, page by page.
+func streamRecords[T any](endpoint string, params url.Values) error {
+	checkFilterFor[T]()
+	name := strings.TrimPrefix(endpoint, "/")
+	return apiGetPagesContext(context.Background(), endpointURL(endpoint), params, func(page MultiDocumentResponse[json.RawMessage]) error {
+		for _, d := range page.Data {
+			var doc T
+			if err := json.Unmarshal(d, &doc); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			if err := ndjsonOut.write(name, reflect.ValueOf(doc)); err != nil {
+				return err
+			}
+		}
+		ndjsonOut.flush()
+		return nil
+	})
+}
+
+// streamEndpoints is the NDJSON form of fetchEndpointsJSON: every page is
+// written as it arrives, keeping records whose day (if any) is in
+// startDate..endDate. Failed endpoints are reported on stderr.
+func streamEndpoints(startDate, endDate string, reqs []endpointRequest) {
+	errs := streamConcurrently(reqs, func(i int, docs []json.RawMessage) error {
+		m, ok := endpointModels[reqs[i].Endpoint]
+		if !ok {
+			return fmt.Errorf("no record format for %s", reqs[i].Endpoint)
+		}
+		name := strings.TrimPrefix(reqs[i].Endpoint, "/")
+		for _, d := range docs {
+			v, err := m.decodeDoc(d)
+			if err != nil {
+				return err
+			}
+			if day, ok := recordDay(v); ok && (day < startDate || day > endDate) {
+				continue
+			}
+			if err := ndjsonOut.write(name, v); err != nil {
+				return err
+			}
+		}
+		ndjsonOut.flush()
+		return nil
+	})
+
+	failed := false
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", strings.TrimPrefix(reqs[i].Endpoint, "/"), err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// writeEndpointsTable is the CSV/TSV form of fetchEndpointsJSON: each
+// endpoint's records limited to startDate..endDate, combined into one table
+// when there are several. Failed endpoints are reported on stderr.
+func writeEndpointsTable(startDate, endDate string, reqs []endpointRequest, results []endpointResponse) {
+	var out *recordTable
+	failed := false
+	for i, r := range results {
+		name := strings.TrimPrefix(reqs[i].Endpoint, "/")
+		var table *recordTable
+		err := r.Err
+		if err == nil {
+			table, err = endpointTable(reqs[i].Endpoint, r.Body, reqs[i].Single)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", name, err)
+			failed = true
+			continue
+		}
+		table.keepDays(startDate, endDate)
+		if len(reqs) == 1 {
+			out = table
+			continue
+		}
+		if out == nil {
+			out = &recordTable{}
+		}
+		out.merge(name, table)
+	}
+	if out != nil {
+		writeTable(out)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
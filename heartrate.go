@@ -0,0 +1,710 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayWindow returns the local-time window from startDate 00:00 up to the
+// midnight that ends endDate.
+func dayWindow(startDate string, endDate string) (start time.Time, end time.Time) {
+	start, _ = time.ParseInLocation(dateLayout, startDate, time.Local)
+	end, _ = time.ParseInLocation(dateLayout, endDate, time.Local)
+	return start, end.AddDate(0, 0, 1)
+}
+
+// heartRateParams builds the start_datetime/end_datetime query for /heartrate.
+func heartRateParams(start time.Time, end time.Time) url.Values {
+	params := url.Values{}
+	params.Set("start_datetime", start.Format(time.RFC3339))
+	params.Set("end_datetime", end.Format(time.RFC3339))
+	return params
+}
+
+// HeartRateQuery is the parsed form of `oura heartrate` arguments.
+type HeartRateQuery struct {
+	Start    time.Time
+	End      time.Time
+	Detail   bool
+	Interval time.Duration
+	MaxHR    int
+	Zones    []int
+}
+
+const defaultHeartRateInterval = time.Hour
+
+// Default zone boundaries as a percentage of max HR (Z1 starts at 50%).
+var defaultHeartRateZones = []int{50, 60, 70, 80, 90}
+
+// parseHeartRateArgs resolves `oura heartrate [date|range] [--since <t>]
+// [--until <t>] [--detail] [--interval <d>] [--max-hr <bpm>] [--zones <pcts>]`.
+// --since/--until accept a clock time (HH:MM, anchored on the first/last day
+// of the range) or a full datetime. A clock-only --until that is not after
+// --since rolls over to the next day, so `--since 22:00 --until 07:00` covers
+// the night. --interval and --zones imply --detail.
+func parseHeartRateArgs(args []string, opts Options) (q HeartRateQuery, err error) {
+	args, q.Detail = cutBoolFlag(args, "detail", "detailed")
+	flags, pos, err := parseLongFlags(args)
+	if err != nil {
+		return q, err
+	}
+	if len(pos) > 1 {
+		return q, fmt.Errorf("unexpected args: %s", strings.Join(pos[1:], " "))
+	}
+	for name := range flags {
+		switch name {
+		case "since", "until", "interval", "max-hr", "max_hr", "zones":
+		default:
+			return q, fmt.Errorf("unknown flag: --%s", name)
+		}
+	}
+
+	startDate, endDate, err := parseDateRangeArg(pos, opts)
+	if err != nil {
+		return q, err
+	}
+	q.Start, q.End = dayWindow(startDate, endDate)
+
+	since := flags["since"]
+	until := flags["until"]
+	if since != "" {
+		t, _, err := parseClockOrDatetime(since, startDate)
+		if err != nil {
+			return q, fmt.Errorf("invalid --since: %w", err)
+		}
+		q.Start = t
+	}
+	if until != "" {
+		t, clockOnly, err := parseClockOrDatetime(until, endDate)
+		if err != nil {
+			return q, fmt.Errorf("invalid --until: %w", err)
+		}
+		if clockOnly && since != "" && !t.After(q.Start) {
+			t = t.AddDate(0, 0, 1)
+		}
+		q.End = t
+	}
+	if !q.End.After(q.Start) {
+		return q, fmt.Errorf("empty time window: %s → %s", q.Start.Format(time.RFC3339), q.End.Format(time.RFC3339))
+	}
+
+	q.Interval = defaultHeartRateInterval
+	if v := flags["interval"]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < time.Minute {
+			return q, fmt.Errorf("invalid --interval: %q (e.g. 5m, 15m, 1h)", v)
+		}
+		q.Interval = d
+		q.Detail = true
+	}
+
+	q.MaxHR = config.MaxHR
+	if v := firstFlag(flags, "max-hr", "max_hr"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 100 || n > 250 {
+			return q, fmt.Errorf("invalid --max-hr: %q", v)
+		}
+		q.MaxHR = n
+	}
+
+	q.Zones = defaultHeartRateZones
+	if v := flags["zones"]; v != "" {
+		zones, err := parseZoneList(v)
+		if err != nil {
+			return q, err
+		}
+		q.Zones = zones
+		q.Detail = true
+	} else if len(config.HRZones) > 0 {
+		if err := checkZones(config.HRZones); err != nil {
+			return q, fmt.Errorf("invalid hr_zones in config.json: %v (%v)", config.HRZones, err)
+		}
+		q.Zones = config.HRZones
+	}
+	return q, nil
+}
+
+func heartRateQueryOrExit(pa ParsedArgs) HeartRateQuery {
+	q, err := parseHeartRateArgs(pa.Args, pa.Opts)
+	if err != nil {
+		exitErr(err)
+	}
+	return q
+}
+
+// parseZoneList parses ascending zone boundaries like "50,60,70,80,90",
+// given as percentages of max HR.
+func parseZoneList(v string) ([]int, error) {
+	var zones []int
+	for _, part := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(part, "%")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --zones: %q (expected ascending percentages of max HR, e.g. 50,60,70,80,90)", v)
+		}
+		zones = append(zones, n)
+	}
+	if err := checkZones(zones); err != nil {
+		return nil, fmt.Errorf("invalid --zones: %q (%v)", v, err)
+	}
+	return zones, nil
+}
+
+// checkZones reports whether zone boundaries are ascending percentages of
+// max HR, as heartRateZones expects.
+func checkZones(zones []int) error {
+	for i, n := range zones {
+		if n <= 0 || n > 120 {
+			return fmt.Errorf("boundary %d%% is outside 1-120%%", n)
+		}
+		if i > 0 && n <= zones[i-1] {
+			return fmt.Errorf("boundaries must be ascending")
+		}
+	}
+	return nil
+}
+
+// parseClockOrDatetime parses HH:MM[:SS] on the given day, or a full
+// datetime (RFC3339, or YYYY-MM-DDTHH:MM in local time).
+func parseClockOrDatetime(v string, day string) (t time.Time, clockOnly bool, err error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if c, err := time.Parse(layout, v); err == nil {
+			d, _ := time.ParseInLocation(dateLayout, day, time.Local)
+			return time.Date(d.Year(), d.Month(), d.Day(), c.Hour(), c.Minute(), c.Second(), 0, time.Local), true, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.Local(), false, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("%q (expected HH:MM or a datetime)", v)
+}
+
+// windowLabel renders a heart-rate window as a day, a day range, or a
+// clock range when it does not fall on midnight boundaries.
+func windowLabel(start time.Time, end time.Time) string {
+	isMidnight := func(t time.Time) bool {
+		return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+	}
+	if isMidnight(start) && isMidnight(end) {
+		first := start.Format(dateLayout)
+		last := end.AddDate(0, 0, -1).Format(dateLayout)
+		if first == last {
+			return first
+		}
+		return first + ".." + last
+	}
+	return start.Format("2006-01-02 15:04") + " → " + end.Format("2006-01-02 15:04")
+}
+
+func fetchHeartRate(start time.Time, end time.Time) {
+	label := windowLabel(start, end)
+
+	body, err := apiGetAll("/heartrate", heartRateParams(start, end))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printHeartRateWindow(label, body)
+}
+
+// printHeartRateWindow summarises a /heartrate response for the window
+// described by label.
+func printHeartRateWindow(label string, body []byte) {
+	var data HeartRateResponse
+	json.Unmarshal(body, &data)
+
+	if len(data.Data) == 0 {
+		fmt.Println("No heart rate data for", label)
+		return
+	}
+
+	var min, max, sum int
+	min = 999
+	for _, hr := range data.Data {
+		if hr.BPM < min {
+			min = hr.BPM
+		}
+		if hr.BPM > max {
+			max = hr.BPM
+		}
+		sum += hr.BPM
+	}
+	avg := sum / len(data.Data)
+
+	fmt.Printf("❤️  Heart Rate - %s\n", label)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Printf("Readings:  %d\n", len(data.Data))
+	fmt.Printf("Min:       %d bpm\n", min)
+	fmt.Printf("Max:       %d bpm\n", max)
+	fmt.Printf("Average:   %d bpm\n", avg)
+}
+
+func fetchHeartRateJSON(start time.Time, end time.Time) {
+	params := heartRateParams(start, end)
+	// The window ends at midnight or at --until; either way the last day
+	// is the one it ends in.
+	startDate := start.Format(dateLayout)
+	endDate := end.Add(-time.Nanosecond).Format(dateLayout)
+	out := JSONOutput{
+		Command:       "heartrate",
+		Date:          startDate,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		StartDatetime: params.Get("start_datetime"),
+		EndDatetime:   params.Get("end_datetime"),
+		Endpoints:     make(map[string]EndpointResult, 1),
+	}
+
+	if jsonVersion == 2 {
+		reqs := []endpointRequest{{Endpoint: "/heartrate", Params: params}}
+		writeDaysJSON("heartrate", startDate, endDate, reqs, fetchConcurrently(reqs))
+		return
+	}
+	checkFilterFor[HeartRateRecord]()
+	if ndjsonOutput() {
+		if err := streamRecords[HeartRateRecord]("/heartrate", params); err != nil {
+			exitErr(err)
+		}
+		return
+	}
+	body, err := apiGetAll("/heartrate", params)
+	if templateOutput() {
+		if err != nil {
+			exitErr(err)
+		}
+		var resp MultiDocumentResponse[HeartRateRecord]
+		if err := json.Unmarshal(body, &resp); err != nil {
+			exitErr(fmt.Errorf("failed to parse response: %w", err))
+		}
+		renderRecords("heartrate", resp.Data)
+		return
+	}
+	if tabularOutput() {
+		if err != nil {
+			exitErr(err)
+		}
+		t, err := endpointTable("/heartrate", body, false)
+		if err != nil {
+			exitErr(err)
+		}
+		writeTable(t)
+		return
+	}
+	if err == nil && filteredOutput() {
+		body, err = filterBody(endpointModels["/heartrate"], body, false, "", "")
+	}
+	if err != nil {
+		out.Endpoints["heartrate"] = EndpointResult{Error: err.Error()}
+	} else {
+		out.Endpoints["heartrate"] = EndpointResult{Data: json.RawMessage(body)}
+	}
+
+	writeJSONToStdout(out)
+}
+
+// heartRateSources lists HeartRateSource values in display order.
+var heartRateSources = []string{"awake", "rest", "sleep", "session", "workout", "live"}
+
+// Readings further apart than this are treated as a gap rather than
+// continuous time when computing zone durations.
+const maxHeartRateSampleGap = 5 * time.Minute
+
+type HeartRateStats struct {
+	Readings int     `json:"readings"`
+	Min      int     `json:"min"`
+	Max      int     `json:"max"`
+	Avg      float64 `json:"avg"`
+	Seconds  int     `json:"seconds"`
+}
+
+type HeartRateSourceStats struct {
+	Source string `json:"source"`
+	HeartRateStats
+}
+
+type HeartRateBucket struct {
+	Start    string                    `json:"start"`
+	All      HeartRateStats            `json:"all"`
+	BySource map[string]HeartRateStats `json:"by_source,omitempty"`
+}
+
+type HeartRateZone struct {
+	Name    string `json:"name"`
+	MinBPM  int    `json:"min_bpm"`
+	MaxBPM  int    `json:"max_bpm,omitempty"`
+	Seconds int    `json:"seconds"`
+}
+
+type HeartRateDetail struct {
+	StartDatetime   string                 `json:"start_datetime"`
+	EndDatetime     string                 `json:"end_datetime"`
+	IntervalSeconds int                    `json:"interval_seconds"`
+	Summary         HeartRateStats         `json:"summary"`
+	Sources         []HeartRateSourceStats `json:"sources"`
+	Buckets         []HeartRateBucket      `json:"buckets"`
+	MaxHR           int                    `json:"max_hr,omitempty"`
+	MaxHRSource     string                 `json:"max_hr_source,omitempty"`
+	Zones           []HeartRateZone        `json:"zones,omitempty"`
+	ZonesError      string                 `json:"zones_error,omitempty"`
+}
+
+// heartRateSample is a reading with its parsed time and the duration it
+// represents (time until the next reading, capped at maxHeartRateSampleGap).
+type heartRateSample struct {
+	HeartRateRecord
+	At       time.Time
+	Duration time.Duration
+}
+
+func toHeartRateSamples(records []HeartRateRecord, end time.Time) []heartRateSample {
+	samples := make([]heartRateSample, 0, len(records))
+	for _, r := range records {
+		t, err := time.Parse(time.RFC3339, r.Timestamp)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, heartRateSample{HeartRateRecord: r, At: t.Local()})
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].At.Before(samples[j].At) })
+	for i := range samples {
+		next := end
+		if i+1 < len(samples) {
+			next = samples[i+1].At
+		}
+		d := next.Sub(samples[i].At)
+		if d > maxHeartRateSampleGap {
+			d = maxHeartRateSampleGap
+		}
+		if d < 0 {
+			d = 0
+		}
+		samples[i].Duration = d
+	}
+	return samples
+}
+
+// statsAccumulator builds HeartRateStats incrementally.
+type statsAccumulator struct {
+	n, min, max, sum int
+	dur              time.Duration
+}
+
+func (a *statsAccumulator) add(s heartRateSample) {
+	if a.n == 0 || s.BPM < a.min {
+		a.min = s.BPM
+	}
+	if s.BPM > a.max {
+		a.max = s.BPM
+	}
+	a.n++
+	a.sum += s.BPM
+	a.dur += s.Duration
+}
+
+func (a *statsAccumulator) stats() HeartRateStats {
+	if a.n == 0 {
+		return HeartRateStats{}
+	}
+	return HeartRateStats{
+		Readings: a.n,
+		Min:      a.min,
+		Max:      a.max,
+		Avg:      float64(a.sum) / float64(a.n),
+		Seconds:  int(a.dur.Seconds()),
+	}
+}
+
+func analyzeHeartRate(records []HeartRateRecord, q HeartRateQuery) HeartRateDetail {
+	samples := toHeartRateSamples(records, q.End)
+
+	detail := HeartRateDetail{
+		StartDatetime:   q.Start.Format(time.RFC3339),
+		EndDatetime:     q.End.Format(time.RFC3339),
+		IntervalSeconds: int(q.Interval.Seconds()),
+	}
+
+	var total statsAccumulator
+	bySource := map[string]*statsAccumulator{}
+	nBuckets := int((q.End.Sub(q.Start) + q.Interval - 1) / q.Interval)
+	buckets := make([]statsAccumulator, nBuckets)
+	bucketSources := make([]map[string]*statsAccumulator, nBuckets)
+
+	for _, s := range samples {
+		total.add(s)
+		src := bySource[s.Source]
+		if src == nil {
+			src = &statsAccumulator{}
+			bySource[s.Source] = src
+		}
+		src.add(s)
+
+		i := int(s.At.Sub(q.Start) / q.Interval)
+		if i < 0 || i >= nBuckets {
+			continue
+		}
+		buckets[i].add(s)
+		if bucketSources[i] == nil {
+			bucketSources[i] = map[string]*statsAccumulator{}
+		}
+		bs := bucketSources[i][s.Source]
+		if bs == nil {
+			bs = &statsAccumulator{}
+			bucketSources[i][s.Source] = bs
+		}
+		bs.add(s)
+	}
+
+	detail.Summary = total.stats()
+	for _, name := range orderedSources(bySource) {
+		detail.Sources = append(detail.Sources, HeartRateSourceStats{Source: name, HeartRateStats: bySource[name].stats()})
+	}
+
+	detail.Buckets = make([]HeartRateBucket, nBuckets)
+	for i := range buckets {
+		b := HeartRateBucket{
+			Start: q.Start.Add(time.Duration(i) * q.Interval).Format(time.RFC3339),
+			All:   buckets[i].stats(),
+		}
+		if len(bucketSources[i]) > 0 {
+			b.BySource = make(map[string]HeartRateStats, len(bucketSources[i]))
+			for name, acc := range bucketSources[i] {
+				b.BySource[name] = acc.stats()
+			}
+		}
+		detail.Buckets[i] = b
+	}
+
+	return detail
+}
+
+// orderedSources returns the keys of m in heartRateSources order, followed by
+// any unknown sources alphabetically.
+func orderedSources[V any](m map[string]V) []string {
+	var out []string
+	for _, name := range heartRateSources {
+		if _, ok := m[name]; ok {
+			out = append(out, name)
+		}
+	}
+	var extra []string
+	for name := range m {
+		known := false
+		for _, k := range heartRateSources {
+			if k == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(out, extra...)
+}
+
+// heartRateZones computes time spent in each zone. Zone boundaries are
+// percentages of maxHR; time below the first boundary is reported as
+// "Below Z1".
+func heartRateZones(records []HeartRateRecord, q HeartRateQuery, maxHR int) []HeartRateZone {
+	bounds := make([]int, len(q.Zones))
+	for i, pct := range q.Zones {
+		bounds[i] = (maxHR*pct + 50) / 100
+	}
+
+	zones := make([]HeartRateZone, len(bounds)+1)
+	zones[0] = HeartRateZone{Name: "Below Z1", MinBPM: 0, MaxBPM: bounds[0] - 1}
+	for i := range bounds {
+		z := HeartRateZone{Name: fmt.Sprintf("Z%d", i+1), MinBPM: bounds[i]}
+		if i+1 < len(bounds) {
+			z.MaxBPM = bounds[i+1] - 1
+		}
+		zones[i+1] = z
+	}
+
+	for _, s := range toHeartRateSamples(records, q.End) {
+		i := sort.Search(len(bounds), func(i int) bool { return bounds[i] > s.BPM })
+		zones[i].Seconds += int(s.Duration.Seconds())
+	}
+	return zones
+}
+
+// resolveMaxHR picks max HR from --max-hr/config, falling back to the
+// 220 - age estimate using /personal_info.
+func resolveMaxHR(q HeartRateQuery) (maxHR int, source string, err error) {
+	if q.MaxHR > 0 {
+		return q.MaxHR, "configured", nil
+	}
+	age, err := personalAge()
+	if err != nil {
+		return 0, "", fmt.Errorf("max HR unknown (set --max-hr or \"max_hr\" in config.json): %w", err)
+	}
+	return 220 - age, fmt.Sprintf("220 - age %d", age), nil
+}
+
+// heartRateBucketTable flattens the buckets like any record, except that
+// by_source becomes a by_source.<source>.* group of columns for each source
+// in the window rather than one JSON column.
+func heartRateBucketTable(detail HeartRateDetail) *recordTable {
+	t := newRecordTable(reflect.TypeFor[HeartRateBucket]())
+	bySource := slices.Index(t.Columns, "by_source")
+	t.Columns = slices.Delete(t.Columns, bySource, bySource+1)
+	stats := newRecordTable(reflect.TypeFor[HeartRateStats]()).Columns
+	for _, src := range detail.Sources {
+		for _, c := range stats {
+			t.Columns = append(t.Columns, "by_source."+src.Source+"."+c)
+		}
+	}
+
+	for _, b := range detail.Buckets {
+		row := recordValues(reflect.ValueOf(b))
+		row = slices.Delete(row, bySource, bySource+1)
+		for _, src := range detail.Sources {
+			if st, ok := b.BySource[src.Source]; ok {
+				row = append(row, recordValues(reflect.ValueOf(st))...)
+			} else {
+				row = append(row, make([]any, len(stats))...)
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func fetchHeartRateDetail(q HeartRateQuery, asJSON bool) {
+	if asJSON && jsonVersion == 2 {
+		exitErr(fmt.Errorf("heartrate --detail has no --json=v2 output"))
+	}
+	body, err := apiGetAll("/heartrate", heartRateParams(q.Start, q.End))
+	if err != nil {
+		exitErr(err)
+	}
+	var data HeartRateResponse
+	if err := json.Unmarshal(body, &data); err != nil {
+		exitErr(fmt.Errorf("failed to parse response: %w", err))
+	}
+
+	detail := analyzeHeartRate(data.Data, q)
+	if maxHR, source, err := resolveMaxHR(q); err != nil {
+		detail.ZonesError = err.Error()
+	} else {
+		detail.MaxHR = maxHR
+		detail.MaxHRSource = source
+		detail.Zones = heartRateZones(data.Data, q, maxHR)
+	}
+
+	// CSV/TSV, NDJSON and --fields/--where carry one record per bucket;
+	// the summary and zones are JSON-only. A template sees the whole detail.
+	if asJSON && tabularOutput() {
+		writeTable(heartRateBucketTable(detail))
+		return
+	}
+	if asJSON && ndjsonOutput() {
+		writeNDJSON("heartrate_bucket", detail.Buckets)
+		return
+	}
+	if asJSON && templateOutput() {
+		renderTemplate("heartrate", detail)
+		return
+	}
+	if asJSON && filteredOutput() {
+		writeJSONToStdout(map[string]any{"data": filterValues(detail.Buckets)})
+		return
+	}
+	if asJSON {
+		writeJSONToStdout(detail)
+		return
+	}
+	printHeartRateDetail(detail, q)
+}
+
+func printHeartRateDetail(d HeartRateDetail, q HeartRateQuery) {
+	label := windowLabel(q.Start, q.End)
+	if d.Summary.Readings == 0 {
+		fmt.Println("No heart rate data for", label)
+		return
+	}
+
+	fmt.Printf("❤️  Heart Rate - %s\n", label)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Printf("Readings:  %d\n", d.Summary.Readings)
+	fmt.Printf("Min:       %d bpm\n", d.Summary.Min)
+	fmt.Printf("Max:       %d bpm\n", d.Summary.Max)
+	fmt.Printf("Average:   %.0f bpm\n", d.Summary.Avg)
+
+	fmt.Println()
+	fmt.Println("By source:")
+	for _, s := range d.Sources {
+		fmt.Printf("  %-8s %5d readings  min %3d  avg %3.0f  max %3d  %s\n",
+			s.Source, s.Readings, s.Min, s.Avg, s.Max, formatDuration(s.Seconds))
+	}
+
+	sources := make([]string, 0, len(d.Sources))
+	for _, s := range d.Sources {
+		sources = append(sources, s.Source)
+	}
+	timeLayout := "15:04"
+	if q.End.Sub(q.Start) > 24*time.Hour {
+		timeLayout = "Mon 01-02 15:04"
+	}
+
+	fmt.Println()
+	fmt.Printf("Timeline (every %s, avg bpm):\n", formatDuration(int(q.Interval.Seconds())))
+	fmt.Printf("  %-*s  %5s", len(timeLayout), "Time", "all")
+	for _, src := range sources {
+		fmt.Printf("  %7s", src)
+	}
+	fmt.Println()
+	for _, b := range d.Buckets {
+		t, _ := time.Parse(time.RFC3339, b.Start)
+		fmt.Printf("  %-*s  %5s", len(timeLayout), t.Local().Format(timeLayout), formatBPM(b.All))
+		for _, src := range sources {
+			fmt.Printf("  %7s", formatBPM(b.BySource[src]))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println()
+	if d.ZonesError != "" {
+		fmt.Println("Zones: unavailable -", d.ZonesError)
+		return
+	}
+	fmt.Printf("Zones (max HR %d bpm, %s):\n", d.MaxHR, d.MaxHRSource)
+	var zoneTotal int
+	for _, z := range d.Zones {
+		zoneTotal += z.Seconds
+	}
+	for _, z := range d.Zones {
+		bpm := fmt.Sprintf("%d+ bpm", z.MinBPM)
+		if z.MaxBPM > 0 {
+			bpm = fmt.Sprintf("%d-%d bpm", z.MinBPM, z.MaxBPM)
+		}
+		if z.MinBPM == 0 {
+			bpm = fmt.Sprintf("<%d bpm", z.MaxBPM+1)
+		}
+		bar := ""
+		if zoneTotal > 0 {
+			bar = strings.Repeat("█", z.Seconds*20/zoneTotal)
+		}
+		fmt.Printf("  %-8s %-12s %8s  %s\n", z.Name, bpm, formatDuration(z.Seconds), bar)
+	}
+}
+
+func formatBPM(s HeartRateStats) string {
+	if s.Readings == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", s.Avg)
+}
@@ -26,15 +26,25 @@ func printHelp(cmd string, args []string) {
 		printCompletionUsage()
 	case "personal-info", "personal_info", "personal":
 		printPersonalInfoUsage()
-	case "tag":
-		printTagUsage()
-	case "enhanced-tag", "enhanced_tag":
-		printEnhancedTagUsage()
-	case "session":
-		printSessionUsage()
 	case "webhook":
 		printWebhookUsage()
+	case "api":
+		printAPIUsage()
+	case "cache":
+		printCacheUsage()
+	case "sync":
+		printSyncUsage()
+	case "export":
+		printExportUsage()
+	case "mock-server":
+		printMockServerUsage()
+	case "schema":
+		printSchemaUsage()
 	default:
+		if c := findCollection(cmd); c != nil {
+			printCollectionUsage(*c)
+			return
+		}
 		// For legacy date-based commands, keep help short.
 		fmt.Fprintf(os.Stderr, "Unknown command for help: %s\n\n", cmd)
 		printUsage()
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// httpClient is shared by every API request so they all get a timeout.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// retryPolicy controls sendRequest. Configured from config.json by
+// configureHTTP.
+var retryPolicy = struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// configureHTTP applies the timeout and retry settings from config.json.
+func configureHTTP() error {
+	if config.Timeout != "" {
+		d, err := time.ParseDuration(config.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout in config: %q", config.Timeout)
+		}
+		httpClient.Timeout = d
+	}
+	if config.MaxRetries != nil {
+		if *config.MaxRetries < 0 {
+			return fmt.Errorf("invalid max_retries in config: %d", *config.MaxRetries)
+		}
+		retryPolicy.MaxRetries = *config.MaxRetries
+	}
+	if config.RetryMaxWait != "" {
+		d, err := time.ParseDuration(config.RetryMaxWait)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid retry_max_wait in config: %q", config.RetryMaxWait)
+		}
+		retryPolicy.MaxDelay = d
+	}
+	return nil
+}
+
+// sendRequest performs one API call, retrying transient failures:
+// 429 (honouring Retry-After) for any method, and network errors and 5xx
+// responses for idempotent methods only, so a POST is never sent twice
+// after the server may have acted on it. The final response is returned
+// whatever its status; err is set only when no response was received or
+// ctx ended.
+func sendRequest(ctx context.Context, method, url string, body []byte, header http.Header) (respBody []byte, status int, err error) {
+	if offline {
+		return nil, 0, fmt.Errorf("%w: %s %s needs the network", errOffline, method, url)
+	}
+	idempotent := method != http.MethodPost && method != http.MethodPatch
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, 0, err
+		}
+		for k, vs := range header {
+			req.Header[k] = vs
+		}
+
+		var retryAfter string
+		resp, err := httpClient.Do(req)
+		if err == nil {
+			respBody, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			status = resp.StatusCode
+			retryAfter = resp.Header.Get("Retry-After")
+		}
+
+		var reason string
+		switch {
+		case ctx.Err() != nil:
+			return nil, 0, ctx.Err()
+		case err != nil && idempotent:
+			reason = err.Error()
+		case err != nil:
+			return nil, 0, err
+		case status == http.StatusTooManyRequests:
+			reason = "rate limited"
+		case status >= 500 && idempotent:
+			reason = fmt.Sprintf("HTTP %d", status)
+		default:
+			return respBody, status, nil
+		}
+
+		if attempt >= retryPolicy.MaxRetries {
+			if err != nil {
+				return nil, 0, err
+			}
+			return respBody, status, nil
+		}
+
+		wait := backoffDelay(attempt)
+		if d, ok := parseRetryAfter(retryAfter, time.Now()); ok {
+			wait = min(d, retryPolicy.MaxDelay)
+		}
+		fmt.Fprintf(os.Stderr, "%s %s: %s; retrying in %s (%d/%d)\n",
+			method, req.URL.Path, reason, wait.Round(100*time.Millisecond), attempt+1, retryPolicy.MaxRetries)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		}
+	}
+}
+
+// backoffDelay is exponential backoff with full jitter, capped at MaxDelay.
+func backoffDelay(attempt int) time.Duration {
+	d := retryPolicy.BaseDelay << attempt
+	if d <= 0 || d > retryPolicy.MaxDelay {
+		d = retryPolicy.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or HTTP-date form.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
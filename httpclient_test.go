@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setRetryPolicy replaces retryPolicy for the duration of the test.
+func setRetryPolicy(t *testing.T, maxRetries int, base, maxDelay time.Duration) {
+	prev := retryPolicy
+	retryPolicy.MaxRetries = maxRetries
+	retryPolicy.BaseDelay = base
+	retryPolicy.MaxDelay = maxDelay
+	t.Cleanup(func() { retryPolicy = prev })
+}
+
+// flakyServer answers the first fails requests with status (and Retry-After,
+// if set) and the rest with 200, counting every request.
+func flakyServer(t *testing.T, fails int, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(hits.Add(1)) <= fails {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestSendRequestRetryAfter(t *testing.T) {
+	// The backoff alone would outlast the deadline, so the request only
+	// succeeds in time if Retry-After: 0 is honoured.
+	setRetryPolicy(t, 3, time.Minute, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, method := range []string{"GET", "POST"} {
+		srv, hits := flakyServer(t, 2, http.StatusTooManyRequests, "0")
+		body, status, err := sendRequest(ctx, method, srv.URL, nil, nil)
+		if err != nil || status != 200 || string(body) != `{"ok":true}` {
+			t.Fatalf("%s: got %d %q, %v", method, status, body, err)
+		}
+		if n := hits.Load(); n != 3 {
+			t.Errorf("%s: server saw %d requests, want 3", method, n)
+		}
+	}
+}
+
+func TestSendRequestServerErrors(t *testing.T) {
+	setRetryPolicy(t, 2, time.Millisecond, time.Millisecond)
+
+	tests := []struct {
+		method string
+		hits   int32
+	}{
+		{"GET", 3}, // the first try and MaxRetries retries, then give up
+		{"DELETE", 3},
+		{"PUT", 3},
+		{"POST", 1}, // never resent: the server may have acted on it
+		{"PATCH", 1},
+	}
+	for _, tt := range tests {
+		srv, hits := flakyServer(t, 10, http.StatusServiceUnavailable, "")
+		_, status, err := sendRequest(context.Background(), tt.method, srv.URL, nil, nil)
+		if err != nil || status != http.StatusServiceUnavailable {
+			t.Errorf("%s: got status %d, %v; want the final 503", tt.method, status, err)
+		}
+		if n := hits.Load(); n != tt.hits {
+			t.Errorf("%s: server saw %d requests, want %d", tt.method, n, tt.hits)
+		}
+	}
+}
+
+func TestSendRequestRecovers(t *testing.T) {
+	setRetryPolicy(t, 3, time.Millisecond, time.Millisecond)
+
+	srv, hits := flakyServer(t, 2, http.StatusBadGateway, "")
+	_, status, err := sendRequest(context.Background(), "GET", srv.URL, nil, nil)
+	if err != nil || status != 200 {
+		t.Fatalf("got status %d, %v; want 200 after retrying", status, err)
+	}
+	if n := hits.Load(); n != 3 {
+		t.Errorf("server saw %d requests, want 3", n)
+	}
+
+	// Client errors other than 429 are final.
+	srv, hits = flakyServer(t, 10, http.StatusNotFound, "")
+	if _, status, _ := sendRequest(context.Background(), "GET", srv.URL, nil, nil); status != http.StatusNotFound || hits.Load() != 1 {
+		t.Errorf("404: got status %d after %d requests, want one request", status, hits.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		v    string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Wed, 14 Oct 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 14 Oct 2026 11:59:00 GMT", 0, true}, // already past
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.v, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %t; want %s, %t", tt.v, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Maximum hypnogram width in columns; longer periods are downsampled.
+const hypnogramWidth = 96
+
+const sleepPhaseSlot = 5 * time.Minute
+
+// Stage rows from top to bottom, keyed by sleep_phase_5_min codes.
+var hypnogramStages = []struct {
+	Label string
+	Code  byte
+}{
+	{"Awake", '4'},
+	{"REM", '3'},
+	{"Light", '2'},
+	{"Deep", '1'},
+}
+
+// movement_30_sec codes: 1 = no motion, 2 = restless, 3 = tossing and turning, 4 = active.
+var movementGlyphs = map[byte]rune{'1': ' ', '2': '░', '3': '▒', '4': '▓'}
+
+var sparkGlyphs = []rune("▁▂▃▄▅▆▇█")
+
+// printHypnogram draws sleep stages, movement and overnight HR/HRV traces
+// for one sleep period on a shared bedtime clock axis.
+func printHypnogram(s SleepRecord) {
+	if s.SleepPhase5Min == "" {
+		fmt.Println("Hypnogram:     n/a")
+		return
+	}
+	bedStart, err := time.Parse(time.RFC3339, s.BedtimeStart)
+	if err != nil {
+		fmt.Println("Hypnogram:     n/a")
+		return
+	}
+	bedStart = bedStart.Local()
+
+	phases := s.SleepPhase5Min
+	per := (len(phases) + hypnogramWidth - 1) / hypnogramWidth
+	cols := (len(phases) + per - 1) / per
+	colDur := time.Duration(per) * sleepPhaseSlot
+
+	stages := make([]byte, cols)
+	for c := range stages {
+		end := min((c+1)*per, len(phases))
+		stages[c] = dominantCode(phases[c*per : end])
+	}
+
+	fmt.Printf("Hypnogram (%s per column):\n", formatDuration(int(colDur.Seconds())))
+	for _, st := range hypnogramStages {
+		var b strings.Builder
+		for _, code := range stages {
+			if code == st.Code {
+				b.WriteRune('█')
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		fmt.Printf("  %-6s │%s\n", st.Label, b.String())
+	}
+
+	if s.Movement30Sec != "" {
+		// Ten 30-second movement slots per 5-minute phase slot.
+		moves := s.Movement30Sec
+		perMove := per * 10
+		var b strings.Builder
+		for c := 0; c < cols; c++ {
+			startIdx := c * perMove
+			if startIdx >= len(moves) {
+				b.WriteRune(' ')
+				continue
+			}
+			end := min(startIdx+perMove, len(moves))
+			var peak byte = '1'
+			for i := startIdx; i < end; i++ {
+				if moves[i] > peak && moves[i] <= '4' {
+					peak = moves[i]
+				}
+			}
+			b.WriteRune(movementGlyphs[peak])
+		}
+		fmt.Printf("  %-6s │%s\n", "Move", b.String())
+	}
+
+	if line, lo, hi, ok := sampleSparkline(s.HeartRate, bedStart, cols, colDur); ok {
+		fmt.Printf("  %-6s │%s  %.0f-%.0f bpm\n", "HR", line, lo, hi)
+	}
+	if line, lo, hi, ok := sampleSparkline(s.HRV, bedStart, cols, colDur); ok {
+		fmt.Printf("  %-6s │%s  %.0f-%.0f ms\n", "HRV", line, lo, hi)
+	}
+
+	ticks, labels := clockAxis(bedStart, cols, colDur)
+	fmt.Printf("  %-6s └%s\n", "", ticks)
+	fmt.Printf("  %-6s  %s\n", "", labels)
+
+	if s.Readiness != nil || s.Period != 0 {
+		fmt.Println()
+		fmt.Printf("Period:        %d\n", s.Period)
+		if s.Readiness != nil {
+			fmt.Printf("Readiness:     %d\n", s.Readiness.Score)
+		}
+	}
+}
+
+// dominantCode returns the most frequent stage code in a run of slots,
+// preferring the earliest on ties.
+func dominantCode(slots string) byte {
+	var counts [256]int
+	var best byte
+	for i := 0; i < len(slots); i++ {
+		c := slots[i]
+		counts[c]++
+		if best == 0 || counts[c] > counts[best] {
+			best = c
+		}
+	}
+	return best
+}
+
+// sampleSparkline averages a SampleModel series into cols columns starting
+// at start and renders it as a sparkline scaled to the series range.
+func sampleSparkline(m *SampleModel, start time.Time, cols int, colDur time.Duration) (line string, lo float64, hi float64, ok bool) {
+	if m == nil || len(m.Items) == 0 || m.Interval <= 0 {
+		return "", 0, 0, false
+	}
+	t0, err := time.Parse(time.RFC3339, m.Timestamp)
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	sums := make([]float64, cols)
+	counts := make([]int, cols)
+	step := time.Duration(m.Interval * float64(time.Second))
+	for i, v := range m.Items {
+		if v == nil {
+			continue
+		}
+		c := int(t0.Add(time.Duration(i)*step).Sub(start) / colDur)
+		if c < 0 || c >= cols {
+			continue
+		}
+		sums[c] += *v
+		counts[c]++
+	}
+
+	lo, hi = math.Inf(1), math.Inf(-1)
+	avgs := make([]float64, cols)
+	for c := range sums {
+		if counts[c] == 0 {
+			continue
+		}
+		avgs[c] = sums[c] / float64(counts[c])
+		lo = math.Min(lo, avgs[c])
+		hi = math.Max(hi, avgs[c])
+	}
+	if math.IsInf(lo, 1) {
+		return "", 0, 0, false
+	}
+
+	var b strings.Builder
+	for c := range avgs {
+		if counts[c] == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		idx := len(sparkGlyphs) - 1
+		if hi > lo {
+			idx = int((avgs[c] - lo) / (hi - lo) * float64(len(sparkGlyphs)-1))
+		}
+		b.WriteRune(sparkGlyphs[idx])
+	}
+	return b.String(), lo, hi, true
+}
+
+// clockAxis renders a tick line and matching hour labels for a timeline of
+// cols columns starting at start.
+func clockAxis(start time.Time, cols int, colDur time.Duration) (ticks string, labels string) {
+	tickRunes := []rune(strings.Repeat("─", cols))
+	labelRunes := []rune(strings.Repeat(" ", cols+2))
+	nextFree := 0
+
+	hour := time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), 0, 0, 0, start.Location())
+	if hour.Before(start) {
+		hour = hour.Add(time.Hour)
+	}
+	for ; ; hour = hour.Add(time.Hour) {
+		c := int(hour.Sub(start) / colDur)
+		if c >= cols {
+			break
+		}
+		tickRunes[c] = '┴'
+		label := hour.Format("15")
+		if c >= nextFree && c+len(label) <= len(labelRunes) {
+			copy(labelRunes[c:], []rune(label))
+			nextFree = c + len(label) + 1
+		}
+	}
+	return string(tickRunes), strings.TrimRight(string(labelRunes), " ")
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,24 +14,60 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	redirectURI = "http://localhost:8081/callback"
+	redirectURI    = "http://localhost:8081/callback"
+	defaultAPIHost = "https://api.ouraring.com"
+)
+
+// API endpoints. These default to production and are re-pointed by
+// configureEndpoints for a base-URL override or sandbox mode.
+var (
 	authURL     = "https://cloud.ouraring.com/oauth/authorize"
-	tokenURL    = "https://api.ouraring.com/oauth/token"
-	apiBase     = "https://api.ouraring.com/v2/usercollection"
+	tokenURL    = defaultAPIHost + "/oauth/token"
+	apiHost     = defaultAPIHost
+	apiBase     = apiHost + "/v2/usercollection"
+	webhookBase = apiHost + "/v2/webhook"
+	sandbox     bool
 )
 
 type Config struct {
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
+	// MaxPages caps how many next_token pages a single fetch follows (0 = no limit).
+	MaxPages int `json:"max_pages,omitempty"`
+	// MaxHR and HRZones configure heart-rate zones (zones are % of max HR).
+	MaxHR   int   `json:"max_hr,omitempty"`
+	HRZones []int `json:"hr_zones,omitempty"`
+	// BaseURL overrides the API host (e.g. a local stand-in server); the
+	// OURA_BASE_URL environment variable takes precedence.
+	BaseURL string `json:"base_url,omitempty"`
+	// Sandbox targets the /v2/sandbox/usercollection routes (sample data).
+	Sandbox bool `json:"sandbox,omitempty"`
+	// Timeout is the per-request timeout (default "30s"). MaxRetries bounds
+	// retries of transient failures (default 3, 0 disables) and RetryMaxWait
+	// caps a single backoff or Retry-After wait (default "30s").
+	Timeout      string `json:"timeout,omitempty"`
+	MaxRetries   *int   `json:"max_retries,omitempty"`
+	RetryMaxWait string `json:"retry_max_wait,omitempty"`
+	// Response cache freshness (see cache.go).
+	CacheTTL        string `json:"cache_ttl,omitempty"`
+	CacheUndatedTTL string `json:"cache_undated_ttl,omitempty"`
+	CacheGraceDays  *int   `json:"cache_grace_days,omitempty"`
+	// StoreDir overrides where `oura sync` keeps its local store.
+	StoreDir string `json:"store_dir,omitempty"`
 }
 
 var config Config
 
+// maxPages is the effective page limit for apiGetAll, resolved from config and flags.
+var maxPages int
+
 func loadConfig() error {
 	configPath := filepath.Join(getConfigDir(), "config.json")
 	data, err := os.ReadFile(configPath)
@@ -37,6 +77,57 @@ func loadConfig() error {
 	return json.Unmarshal(data, &config)
 }
 
+// configureEndpoints points the API variables at the configured host and
+// route tree. Precedence: flag/env over config over the production default.
+func configureEndpoints(opts Options) error {
+	host := config.BaseURL
+	if env := os.Getenv("OURA_BASE_URL"); env != "" {
+		host = env
+	}
+	if host != "" {
+		host = strings.TrimRight(host, "/")
+		u, err := url.Parse(host)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid base URL: %q", host)
+		}
+		if host != defaultAPIHost {
+			authURL = host + "/oauth/authorize"
+		}
+		apiHost = host
+		tokenURL = host + "/oauth/token"
+	}
+
+	// OURA_SANDBOX=0 turns off "sandbox": true from config; --sandbox wins
+	// over both.
+	sandbox = config.Sandbox
+	if env := os.Getenv("OURA_SANDBOX"); env != "" {
+		on, err := strconv.ParseBool(env)
+		if err != nil {
+			return fmt.Errorf("invalid OURA_SANDBOX: %q", env)
+		}
+		sandbox = on
+	}
+	if opts.Sandbox {
+		sandbox = true
+	}
+
+	apiBase = apiHost + "/v2/usercollection"
+	if sandbox {
+		apiBase = apiHost + "/v2/sandbox/usercollection"
+	}
+	webhookBase = apiHost + "/v2/webhook"
+	return nil
+}
+
+// endpointURL returns the absolute URL for a usercollection endpoint.
+// personal_info has no sandbox route, so it always uses the live tree.
+func endpointURL(endpoint string) string {
+	if sandbox && endpoint == "/personal_info" {
+		return apiHost + "/v2/usercollection" + endpoint
+	}
+	return apiBase + endpoint
+}
+
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -48,11 +139,34 @@ type StoredToken struct {
 	AccessToken  string    `json:"access_token"`
 	RefreshToken string    `json:"refresh_token"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	// Account identifies the authorised user; it is part of every cache
+	// key so a different account never sees these responses.
+	Account string `json:"account,omitempty"`
 }
 
 type Options struct {
-	JSON bool
-	Help bool
+	// JSON selects machine-readable output; Format picks csv, tsv or
+	// ndjson instead of JSON.
+	JSON   bool
+	Format string
+	// JSONVersion is 2 for --json=v2, per-day merged output.
+	JSONVersion int
+	// Fields (--fields) and Where (--where, repeatable) select record
+	// fields and filter records.
+	Fields string
+	Where  []string
+	// Template is --template text or the --template-file path.
+	Template     string
+	TemplateFile string
+	Help         bool
+	AllPages     bool
+	Sandbox      bool
+	NoCache      bool
+	Refresh      bool
+	Offline      bool
+	MaxPages     int
+	From         string
+	To           string
 }
 
 type ParsedArgs struct {
@@ -67,24 +181,100 @@ type EndpointResult struct {
 }
 
 type JSONOutput struct {
-	Command   string                    `json:"command"`
-	Date      string                    `json:"date"`
-	StartDate string                    `json:"start_date"`
-	EndDate   string                    `json:"end_date"`
-	Endpoints map[string]EndpointResult `json:"endpoints"`
+	Command       string                    `json:"command"`
+	Date          string                    `json:"date"`
+	From          string                    `json:"from,omitempty"`
+	To            string                    `json:"to,omitempty"`
+	StartDate     string                    `json:"start_date"`
+	EndDate       string                    `json:"end_date"`
+	StartDatetime string                    `json:"start_datetime,omitempty"`
+	EndDatetime   string                    `json:"end_datetime,omitempty"`
+	Endpoints     map[string]EndpointResult `json:"endpoints"`
 }
 
 func main() {
-	pa, ok := parseArgs(os.Args)
-	if !ok {
+	pa, err := parseArgs(os.Args)
+	if err != nil {
+		if err != errUsage {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		}
 		printUsage()
 		os.Exit(1)
 	}
 
+	// The mock server stands in for the API and needs no credentials.
+	if pa.Command == "mock-server" {
+		handleMockServer(pa.Args, pa.Opts)
+		return
+	}
+	// Nor does printing the output schema.
+	if pa.Command == "schema" {
+		handleSchema(pa.Args, pa.Opts)
+		return
+	}
+
 	if err := loadConfig(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	if err := configureEndpoints(pa.Opts); err != nil {
+		exitErr(err)
+	}
+	if err := configureHTTP(); err != nil {
+		exitErr(err)
+	}
+	cacheMode.Disabled = pa.Opts.NoCache
+	cacheMode.Refresh = pa.Opts.Refresh
+	offline = pa.Opts.Offline
+	if offline && (pa.Opts.NoCache || pa.Opts.Refresh) {
+		exitErr(fmt.Errorf("--offline reads the cache; it can't be combined with --no-cache or --refresh"))
+	}
+
+	jsonVersion = pa.Opts.JSONVersion
+	if jsonVersion == 2 && (!daysOutputCommand(pa.Command) || (len(pa.Args) > 0 && (pa.Args[0] == "list" || pa.Args[0] == "get"))) {
+		exitErr(fmt.Errorf("--json=v2 is for the metric commands (today, all, sleep, readiness, …)"))
+	}
+	if pa.Opts.Fields != "" || len(pa.Opts.Where) > 0 {
+		if !recordOutputCommand(pa.Command) {
+			exitErr(fmt.Errorf("%s doesn't support --fields or --where", pa.Command))
+		}
+		if pa.Opts.Fields != "" {
+			if outputFilter.Fields, err = parseFields(pa.Opts.Fields); err != nil {
+				exitErr(err)
+			}
+		}
+		for _, w := range pa.Opts.Where {
+			c, err := parseWhere(w)
+			if err != nil {
+				exitErr(err)
+			}
+			outputFilter.Where = append(outputFilter.Where, c)
+		}
+	}
+	outputFormat = pa.Opts.Format
+	if outputFormat != "" && !recordOutputCommand(pa.Command) {
+		exitErr(fmt.Errorf("%s has no %s output", pa.Command, outputFormat))
+	}
+	if pa.Opts.Template != "" || pa.Opts.TemplateFile != "" {
+		if !recordOutputCommand(pa.Command) {
+			exitErr(fmt.Errorf("%s doesn't support --template", pa.Command))
+		}
+		text, err := loadTemplateFlag(pa.Opts.Template, pa.Opts.TemplateFile)
+		if err != nil {
+			exitErr(err)
+		}
+		if outputTemplate, err = parseOutputTemplate(text); err != nil {
+			exitErr(err)
+		}
+	}
+
+	maxPages = config.MaxPages
+	if pa.Opts.MaxPages > 0 {
+		maxPages = pa.Opts.MaxPages
+	}
+	if pa.Opts.AllPages {
+		maxPages = 0
+	}
 
 	// Global help routing.
 	if pa.Opts.Help {
@@ -92,6 +282,15 @@ func main() {
 		return
 	}
 
+	// `oura <collection> list|get ...` addresses the underlying API
+	// collection, including for date-based views like `oura sleep`.
+	if len(pa.Args) > 0 && (pa.Args[0] == "list" || pa.Args[0] == "get") {
+		if c := findCollection(pa.Command); c != nil {
+			handleListGet(*c, pa.Args, pa.Opts)
+			return
+		}
+	}
+
 	switch pa.Command {
 	case "auth":
 		doAuth()
@@ -101,99 +300,130 @@ func main() {
 		handleCompletion(pa.Args)
 	case "personal-info", "personal_info", "personal":
 		handlePersonalInfo(pa.Args, pa.Opts)
-	case "tag":
-		handleTag(pa.Args, pa.Opts)
-	case "enhanced-tag", "enhanced_tag":
-		handleEnhancedTag(pa.Args, pa.Opts)
-	case "session":
-		handleSession(pa.Args, pa.Opts)
+	case "ring", "ring_configuration":
+		handleRing(pa.Args, pa.Opts)
 	case "webhook":
 		handleWebhook(pa.Args, pa.Opts)
+	case "api":
+		handleAPI(pa.Args, pa.Opts)
+	case "cache":
+		handleCache(pa.Args, pa.Opts)
+	case "sync":
+		handleSync(pa.Args, pa.Opts)
+	case "export":
+		handleExport(pa.Args, pa.Opts)
 	case "today":
-		date := parseDateArg(pa.Args)
+		date := dateArgOrExit(pa)
 		if pa.Opts.JSON {
 			fetchAllJSON(date)
 			return
 		}
 		fetchAll(date)
 	case "sleep":
-		date := parseDateArg(pa.Args)
+		var hypnogram bool
+		pa.Args, hypnogram = cutBoolFlag(pa.Args, "hypnogram")
+		if hypnogram && pa.Opts.JSON {
+			exitErr(fmt.Errorf("--hypnogram is part of the text view; it can't be combined with --json, --format, --template, --fields or --where"))
+		}
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchSleepJSON(date)
+			fetchSleepJSON(startDate, endDate)
 			return
 		}
-		fetchSleep(date)
+		fetchSleep(startDate, endDate, hypnogram)
 	case "activity":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchActivityJSON(date)
+			fetchActivityJSON(startDate, endDate)
 			return
 		}
-		fetchActivity(date)
+		fetchActivity(startDate, endDate)
 	case "readiness":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchReadinessJSON(date)
+			fetchReadinessJSON(startDate, endDate)
 			return
 		}
-		fetchReadiness(date)
+		fetchReadiness(startDate, endDate)
 	case "heartrate":
-		date := parseDateArg(pa.Args)
+		q := heartRateQueryOrExit(pa)
+		if q.Detail {
+			fetchHeartRateDetail(q, pa.Opts.JSON)
+			return
+		}
 		if pa.Opts.JSON {
-			fetchHeartRateJSON(date)
+			fetchHeartRateJSON(q.Start, q.End)
 			return
 		}
-		fetchHeartRate(date)
+		fetchHeartRate(q.Start, q.End)
 	case "hrv":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchHRVJSON(date)
+			fetchHRVJSON(startDate, endDate)
 			return
 		}
-		fetchHRV(date)
+		fetchHRV(startDate, endDate)
 	case "stress":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchStressJSON(date)
+			fetchStressJSON(startDate, endDate)
 			return
 		}
-		fetchStress(date)
+		fetchStress(startDate, endDate)
 	case "spo2":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchSpO2JSON(date)
+			fetchSpO2JSON(startDate, endDate)
 			return
 		}
-		fetchSpO2(date)
+		fetchSpO2(startDate, endDate)
 	case "resilience":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchResilienceJSON(date)
+			fetchResilienceJSON(startDate, endDate)
 			return
 		}
-		fetchResilience(date)
+		fetchResilience(startDate, endDate)
 	case "vo2":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchVO2MaxJSON(date)
+			fetchVO2MaxJSON(startDate, endDate)
 			return
 		}
-		fetchVO2Max(date)
+		fetchVO2Max(startDate, endDate)
 	case "workout":
-		date := parseDateArg(pa.Args)
+		startDate, endDate := dateRangeArgOrExit(pa)
+		if pa.Opts.JSON {
+			fetchWorkoutsJSON(startDate, endDate)
+			return
+		}
+		fetchWorkouts(startDate, endDate)
+	case "sleep-time", "sleep_time":
+		startDate, endDate := dateRangeArgOrExit(pa)
+		if pa.Opts.JSON {
+			fetchSleepTimeJSON(startDate, endDate)
+			return
+		}
+		fetchSleepTime(startDate, endDate)
+	case "cardio-age", "cardio_age", "cardiovascular-age":
+		startDate, endDate := dateRangeArgOrExit(pa)
 		if pa.Opts.JSON {
-			fetchWorkoutsJSON(date)
+			fetchCardioAgeJSON(startDate, endDate)
 			return
 		}
-		fetchWorkouts(date)
+		fetchCardioAge(startDate, endDate)
 	case "all":
-		date := parseDateArg(pa.Args)
+		date := dateArgOrExit(pa)
 		if pa.Opts.JSON {
 			fetchAllJSON(date)
 			return
 		}
 		fetchAll(date)
 	default:
+		if c := findCollection(pa.Command); c != nil {
+			handleListGet(*c, pa.Args, pa.Opts)
+			return
+		}
 		printUsage()
 		os.Exit(1)
 	}
@@ -212,23 +442,38 @@ Commands:
   personal-info     Fetch personal info
   today             Show today's summary
   all [date]        Show all metrics for date (default: today)
-  sleep [date]      Show sleep data
-  activity [date]   Show activity data  
-	  readiness [date]  Show readiness data
-	  heartrate [date]  Show heart rate data
-	  hrv [date]        Show heart rate variability (from sleep)
-	  stress [date]     Show daytime stress data
-	  spo2 [date]       Show blood oxygen data
-	  resilience [date] Show resilience data
-	  vo2 [date]        Show VO2 max data
-	  workout [date]    Show workouts
+  sleep [range]     Show sleep data (--hypnogram for stage/HR/HRV timeline)
+  sleep-time [range] Show bedtime recommendations
+  activity [range]  Show activity data  
+	  readiness [range] Show readiness data
+	  heartrate [range] Show heart rate data (--since/--until for a time window)
+	  hrv [range]       Show heart rate variability (from sleep)
+	  stress [range]    Show daytime stress data
+	  spo2 [range]      Show blood oxygen data
+	  resilience [range] Show resilience data
+	  vo2 [range]       Show VO2 max data
+	  workout [range]   Show workouts
+	  cardio-age [range] Show cardiovascular (vascular) age and trend
   json [date]       Raw JSON dump of all data (alias for: all --json)
 
   tag               Manage tags
   enhanced-tag      Manage enhanced tags
   session           Manage sessions
+  rest-mode         Show rest mode periods and episodes
+  ring              Show ring configuration and firmware history
+
+  <collection> list [--start-date <date>] [--end-date <date>]
+  <collection> get <document_id>
+                    Any usercollection type, e.g. daily_sleep, workout,
+                    vO2_max, sleep_time (metric names like sleep also work)
 
   webhook           Manage webhook subscriptions
+  api <path>        Raw API request (e.g. api /daily_sleep --param start_date=2026-10-01)
+  sync [--since d]  Pull all collections into a local store (sync status)
+  export sqlite f   Write a date range to an SQLite database
+  cache stats|clear Inspect or empty the local response cache
+  mock-server       Serve a local stand-in for the Oura API (--port, --fixtures)
+  schema            Print the JSON Schema of --json=v2 output
 
 Webhook subcommands:
   webhook list
@@ -242,18 +487,40 @@ Webhook subcommands:
 Options:
   --help, -h        Show help for a command
   --json, -j         Output JSON to stdout (machine readable)
-
-Date format: YYYY-MM-DD (defaults to today)`)
-}
-
-func parseArgs(argv []string) (pa ParsedArgs, ok bool) {
+  --json=v2         Metric commands: one merged object per day (see oura schema)
+  --format <f>      Output format: text, json, csv, tsv or ndjson (one record per line)
+  --template <t>    Render each record with a Go text/template (today/all: one DaySummary)
+  --template-file <f> Read the --template from a file
+  --fields <list>   Print only these fields, e.g. day,score,contributors.hrv_balance
+  --where <cond>    Keep records matching e.g. 'score<70' (repeat to combine)
+  --max-pages <n>   Follow at most n next_token pages per request
+  --all-pages       Follow next_token until exhausted (default; overrides max_pages)
+  --from <date>     Range start for metric commands
+  --to <date>       Range end for metric commands (default: today)
+  --no-cache        Don't read or write the response cache
+  --refresh         Refetch instead of using cached responses
+  --offline         Use cached responses only (reports days that aren't cached)
+  --sandbox         Use the API sandbox (sample data; also OURA_SANDBOX=1 or "sandbox": true)
+
+Environment:
+  OURA_BASE_URL     Override the API host (also "base_url" in config.json)
+
+Date format: YYYY-MM-DD (defaults to today), or one of:
+  today, yesterday, -3d, -2w, monday..sunday, this-week, last-week,
+  this-month, last-month, 2026-W41 (ISO week)
+Range format: <date>, <start>..<end>, or --from <start> --to <end>`)
+}
+
+var errUsage = errors.New("usage")
+
+func parseArgs(argv []string) (pa ParsedArgs, err error) {
 	if len(argv) < 2 {
-		return ParsedArgs{}, false
+		return ParsedArgs{}, errUsage
 	}
 
 	cmd := argv[1]
 	if cmd == "--help" || cmd == "-h" {
-		return ParsedArgs{Command: "help", Opts: Options{Help: true}}, true
+		return ParsedArgs{Command: "help", Opts: Options{Help: true}}, nil
 	}
 	args := argv[2:]
 	var opts Options
@@ -264,27 +531,230 @@ func parseArgs(argv []string) (pa ParsedArgs, ok bool) {
 		cmd = "all"
 	}
 
-	// Parse global flags in a permissive way: allow them anywhere.
+	// Parse global flags in a permissive way: allow them anywhere. A bare
+	// "--" ends global parsing and hands the rest to the command verbatim.
 	pos := make([]string, 0, len(args))
-	for _, a := range args {
-		switch a {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			pos = append(pos, args[i+1:]...)
+			break
+		}
+		name, val, hasEq := strings.Cut(a, "=")
+		// `oura api` values (query params, request bodies) are never
+		// global flags, whatever they look like.
+		if cmd == "api" && !hasEq && apiValueFlags[name] && i+1 < len(args) {
+			pos = append(pos, a, args[i+1])
+			i++
+			continue
+		}
+		switch name {
 		case "--help", "-h", "help":
 			opts.Help = true
 		case "--json", "-j":
 			opts.JSON = true
+			switch {
+			case !hasEq, val == "v1":
+			case val == "v2":
+				opts.JSONVersion = 2
+			default:
+				return ParsedArgs{}, fmt.Errorf("invalid --json version %q (v1 or v2)", val)
+			}
+		case "--all-pages":
+			opts.AllPages = true
+		case "--sandbox":
+			opts.Sandbox = true
+		case "--no-cache":
+			opts.NoCache = true
+		case "--offline":
+			opts.Offline = true
+		case "--refresh":
+			opts.Refresh = true
+		case "--format":
+			if !hasEq {
+				if i+1 >= len(args) {
+					return ParsedArgs{}, fmt.Errorf("flag %q requires a value", a)
+				}
+				val = args[i+1]
+				i++
+			}
+			f, err := parseOutputFormat(val)
+			if err != nil {
+				return ParsedArgs{}, err
+			}
+			opts.Format = f
+		case "--template", "--template-file":
+			if !hasEq {
+				if i+1 >= len(args) {
+					return ParsedArgs{}, fmt.Errorf("flag %q requires a value", a)
+				}
+				val = args[i+1]
+				i++
+			}
+			if name == "--template" {
+				opts.Template = val
+			} else {
+				opts.TemplateFile = val
+			}
+		case "--fields", "--where":
+			if !hasEq {
+				if i+1 >= len(args) {
+					return ParsedArgs{}, fmt.Errorf("flag %q requires a value", a)
+				}
+				val = args[i+1]
+				i++
+			}
+			if name == "--fields" {
+				opts.Fields = val
+			} else {
+				opts.Where = append(opts.Where, val)
+			}
+		case "--from", "--to":
+			if !hasEq {
+				if i+1 >= len(args) {
+					return ParsedArgs{}, fmt.Errorf("flag %q requires a value", a)
+				}
+				val = args[i+1]
+				i++
+			}
+			if name == "--from" {
+				opts.From = val
+			} else {
+				opts.To = val
+			}
+		case "--max-pages":
+			if !hasEq {
+				if i+1 >= len(args) {
+					return ParsedArgs{}, fmt.Errorf("flag %q requires a value", a)
+				}
+				val = args[i+1]
+				i++
+			}
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return ParsedArgs{}, fmt.Errorf("invalid --max-pages: %q", val)
+			}
+			opts.MaxPages = n
 		default:
 			pos = append(pos, a)
 		}
 	}
 
-	return ParsedArgs{Command: cmd, Args: pos, Opts: opts}, true
+	if opts.JSONVersion == 2 && (opts.Format != "" || opts.Template != "" || opts.TemplateFile != "" || opts.Fields != "" || len(opts.Where) > 0) {
+		return ParsedArgs{}, fmt.Errorf("--json=v2 can't be combined with --format, --template, --fields or --where")
+	}
+	if opts.Template != "" || opts.TemplateFile != "" {
+		if opts.JSON || opts.Format != "" {
+			return ParsedArgs{}, fmt.Errorf("--template can't be combined with --json or --format")
+		}
+		if opts.Fields != "" {
+			return ParsedArgs{}, fmt.Errorf("--template can't be combined with --fields")
+		}
+		opts.JSON = true
+	}
+
+	switch opts.Format {
+	case "text":
+		if opts.JSON {
+			return ParsedArgs{}, fmt.Errorf("--json conflicts with --format text")
+		}
+		opts.Format = ""
+	case "csv", "tsv", "ndjson":
+		if opts.JSON {
+			return ParsedArgs{}, fmt.Errorf("--json conflicts with --format %s", opts.Format)
+		}
+		opts.JSON = true
+	case "json":
+		opts.JSON = true
+		opts.Format = ""
+	}
+
+	// Selected records print as a text table in place of the usual views.
+	if (opts.Fields != "" || len(opts.Where) > 0) && !opts.JSON {
+		opts.JSON = true
+		opts.Format = "text"
+	}
+
+	return ParsedArgs{Command: cmd, Args: pos, Opts: opts}, nil
+}
+
+// parseDateArg resolves a single-day argument (default: today). Range
+// expressions such as last-week are rejected.
+func parseDateArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return time.Now().Format(dateLayout), nil
+	}
+	startDate, endDate, err := resolveDateExpr(args[0], time.Now())
+	if err != nil {
+		return "", err
+	}
+	if startDate != endDate {
+		return "", fmt.Errorf("%q is a range (%s..%s); this command takes a single day", args[0], startDate, endDate)
+	}
+	return startDate, nil
 }
 
-func parseDateArg(args []string) string {
+func dateArgOrExit(pa ParsedArgs) string {
+	date, err := parseDateArg(pa.Args)
+	if err != nil {
+		exitErr(err)
+	}
+	setOfflineRequested(date, date)
+	return date
+}
+
+// parseDateRangeArg resolves the requested day range from a positional
+// `date` or `start..end` argument and the --from/--to flags. Each side may
+// be any expression accepted by resolveDateExpr; a single day yields
+// startDate == endDate.
+func parseDateRangeArg(args []string, opts Options) (startDate string, endDate string, err error) {
+	now := time.Now()
+	from, to := opts.From, opts.To
+	if len(args) > 1 {
+		return "", "", fmt.Errorf("unexpected argument %q (use start..end for a range)", args[1])
+	}
 	if len(args) > 0 {
-		return args[0]
+		if from != "" || to != "" {
+			return "", "", fmt.Errorf("use either a date argument or --from/--to, not both")
+		}
+		a, b, isRange := strings.Cut(args[0], "..")
+		if !isRange {
+			return resolveDateExpr(args[0], now)
+		}
+		from, to = a, b
+	}
+	if from == "" && to == "" {
+		today := now.Format(dateLayout)
+		return today, today, nil
+	}
+	if to == "" {
+		to = "today"
+	}
+	if from == "" {
+		from = to
+	}
+
+	startDate, _, err = resolveDateExpr(from, now)
+	if err != nil {
+		return "", "", err
+	}
+	_, endDate, err = resolveDateExpr(to, now)
+	if err != nil {
+		return "", "", err
+	}
+	if endDate < startDate {
+		return "", "", fmt.Errorf("end date %s is before start date %s", endDate, startDate)
+	}
+	return startDate, endDate, nil
+}
+
+func dateRangeArgOrExit(pa ParsedArgs) (startDate string, endDate string) {
+	startDate, endDate, err := parseDateRangeArg(pa.Args, pa.Opts)
+	if err != nil {
+		exitErr(err)
 	}
-	return time.Now().Format("2006-01-02")
+	setOfflineRequested(startDate, endDate)
+	return startDate, endDate
 }
 
 func getConfigDir() string {
@@ -294,7 +764,15 @@ func getConfigDir() string {
 	return dir
 }
 
+// getTokenPath keeps tokens for a non-default host in their own file so a
+// local stand-in server never overwrites the real credentials.
 func getTokenPath() string {
+	if apiHost != defaultAPIHost {
+		if u, err := url.Parse(apiHost); err == nil {
+			name := strings.NewReplacer(":", "_", ".", "_").Replace(u.Host)
+			return filepath.Join(getConfigDir(), "token-"+name+".json")
+		}
+	}
 	return filepath.Join(getConfigDir(), "token.json")
 }
 
@@ -318,14 +796,21 @@ func loadToken() (*StoredToken, error) {
 	return &token, nil
 }
 
+// tokenMu serializes token refreshes: a refresh token is single-use, so
+// concurrent fetches must not each try to redeem it.
+var tokenMu sync.Mutex
+
 func getValidToken() (string, error) {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
 	token, err := loadToken()
 	if err != nil {
 		return "", fmt.Errorf("not authenticated - run 'oura auth' first")
 	}
 
 	if time.Now().Add(5 * time.Minute).After(token.ExpiresAt) {
-		newToken, err := refreshToken(token.RefreshToken)
+		newToken, err := refreshToken(token)
 		if err != nil {
 			return "", fmt.Errorf("token refresh failed - run 'oura auth' again: %v", err)
 		}
@@ -335,14 +820,17 @@ func getValidToken() (string, error) {
 	return token.AccessToken, nil
 }
 
-func refreshToken(refresh string) (*StoredToken, error) {
+func refreshToken(old *StoredToken) (*StoredToken, error) {
+	if offline {
+		return nil, fmt.Errorf("%w: the access token has expired", errOffline)
+	}
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", refresh)
+	data.Set("refresh_token", old.RefreshToken)
 	data.Set("client_id", config.ClientID)
 	data.Set("client_secret", config.ClientSecret)
 
-	resp, err := http.PostForm(tokenURL, data)
+	resp, err := httpClient.PostForm(tokenURL, data)
 	if err != nil {
 		return nil, err
 	}
@@ -362,6 +850,7 @@ func refreshToken(refresh string) (*StoredToken, error) {
 		AccessToken:  tokenResp.AccessToken,
 		RefreshToken: tokenResp.RefreshToken,
 		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+		Account:      old.Account,
 	}
 	if err := saveToken(stored); err != nil {
 		return nil, err
@@ -441,7 +930,7 @@ func exchangeCode(code string) {
 	data.Set("client_id", config.ClientID)
 	data.Set("client_secret", config.ClientSecret)
 
-	resp, err := http.PostForm(tokenURL, data)
+	resp, err := httpClient.PostForm(tokenURL, data)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Token exchange failed: %v\n", err)
 		os.Exit(1)
@@ -464,6 +953,7 @@ func exchangeCode(code string) {
 		AccessToken:  tokenResp.AccessToken,
 		RefreshToken: tokenResp.RefreshToken,
 		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+		Account:      accountID(tokenResp.AccessToken),
 	}
 
 	if err := saveToken(stored); err != nil {
@@ -474,6 +964,22 @@ func exchangeCode(code string) {
 	fmt.Println("✓ Authenticated successfully!")
 }
 
+// accountID returns the personal_info id of the user an access token belongs
+// to. Without the personal scope it falls back to a random id, so a new login
+// still starts with its own cache.
+func accountID(accessToken string) string {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+accessToken)
+	body, status, err := sendRequest(context.Background(), "GET", endpointURL("/personal_info"), nil, header)
+	var pi PersonalInfoResponse
+	if err == nil && status == 200 && json.Unmarshal(body, &pi) == nil && pi.ID != "" {
+		return pi.ID
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 func openBrowser(url string) {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
@@ -490,38 +996,121 @@ func openBrowser(url string) {
 }
 
 func apiGet(endpoint string, params url.Values) ([]byte, error) {
-	token, err := getValidToken()
+	return apiGetURL(endpointURL(endpoint), params)
+}
+
+// apiGetURL performs an authenticated GET against an absolute API URL.
+func apiGetURL(url string, params url.Values) ([]byte, error) {
+	return apiGetURLContext(context.Background(), url, params)
+}
+
+func apiGetURLContext(ctx context.Context, url string, params url.Values) ([]byte, error) {
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+	if offline {
+		return offlineGet(url)
+	}
+	if body, ok := cacheLookup(url); ok {
+		return body, nil
+	}
+	body, err := apiFetchURL(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
+	cacheStore(url, params, body)
+	return body, nil
+}
 
-	url := apiBase + endpoint
+// apiFetchURL is apiGetURLContext without the response cache and the offline
+// store: it always sends the request.
+func apiFetchURL(ctx context.Context, url string, params url.Values) ([]byte, error) {
 	if len(params) > 0 {
 		url += "?" + params.Encode()
 	}
-
-	req, err := http.NewRequest("GET", url, nil)
+	token, err := getValidToken()
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	resp, err := http.DefaultClient.Do(req)
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	body, status, err := sendRequest(ctx, "GET", url, nil, header)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if status != 200 {
+		return nil, fmt.Errorf("API error %d: %s", status, body)
+	}
+	return body, nil
+}
+
+// apiGetAll fetches a collection endpoint and follows next_token until the
+// results are exhausted or maxPages is reached. The merged pages are returned
+// as a single MultiDocumentResponse body; next_token is set only when paging
+// stopped early.
+func apiGetAll(endpoint string, params url.Values) ([]byte, error) {
+	return apiGetAllURL(endpointURL(endpoint), params)
+}
+
+// apiGetAllURL is apiGetAll for an absolute API URL.
+func apiGetAllURL(fullURL string, params url.Values) ([]byte, error) {
+	return apiGetAllURLContext(context.Background(), fullURL, params)
+}
+
+func apiGetAllURLContext(ctx context.Context, fullURL string, params url.Values) ([]byte, error) {
+	return mergePages(ctx, apiGetURLContext, fullURL, params)
+}
+
+// pageGetter fetches one page of a collection: apiGetURLContext, or
+// apiFetchURL to skip the cache.
+type pageGetter func(ctx context.Context, url string, params url.Values) ([]byte, error)
+
+// mergePages follows next_token with get and merges the pages as
+// apiGetAllURLContext does.
+func mergePages(ctx context.Context, get pageGetter, fullURL string, params url.Values) ([]byte, error) {
+	merged := MultiDocumentResponse[json.RawMessage]{Data: []json.RawMessage{}}
+	err := followPages(ctx, get, fullURL, params, func(page MultiDocumentResponse[json.RawMessage]) error {
+		merged.Data = append(merged.Data, page.Data...)
+		merged.NextToken = page.NextToken
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return json.Marshal(merged)
+}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, body)
+// apiGetPagesContext follows next_token (up to maxPages) and hands each page
+// to fn as it arrives, so callers can stream instead of buffering.
+func apiGetPagesContext(ctx context.Context, fullURL string, params url.Values, fn func(MultiDocumentResponse[json.RawMessage]) error) error {
+	return followPages(ctx, apiGetURLContext, fullURL, params, fn)
+}
+
+func followPages(ctx context.Context, get pageGetter, fullURL string, params url.Values, fn func(MultiDocumentResponse[json.RawMessage]) error) error {
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = append([]string(nil), v...)
 	}
 
-	return body, nil
+	for page := 1; ; page++ {
+		body, err := get(ctx, fullURL, query)
+		if err != nil {
+			return err
+		}
+		var resp MultiDocumentResponse[json.RawMessage]
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		if err := fn(resp); err != nil {
+			return err
+		}
+		if resp.NextToken == "" || (maxPages > 0 && page >= maxPages) {
+			return nil
+		}
+		query.Set("next_token", resp.NextToken)
+	}
 }
 
 // Data types
@@ -531,23 +1120,45 @@ type SleepResponse struct {
 }
 
 type SleepRecord struct {
-	Day                string  `json:"day"`
-	Type               string  `json:"type"`
-	BedtimeStart       string  `json:"bedtime_start"`
-	BedtimeEnd         string  `json:"bedtime_end"`
-	TotalSleepDuration int     `json:"total_sleep_duration"`
-	TimeInBed          int     `json:"time_in_bed"`
-	Efficiency         int     `json:"efficiency"`
-	DeepSleepDuration  int     `json:"deep_sleep_duration"`
-	LightSleepDuration int     `json:"light_sleep_duration"`
-	RemSleepDuration   int     `json:"rem_sleep_duration"`
-	AwakeTime          int     `json:"awake_time"`
-	Latency            int     `json:"latency"`
-	LowestHeartRate    int     `json:"lowest_heart_rate"`
-	AverageHeartRate   float64 `json:"average_heart_rate"`
-	AverageHRV         int     `json:"average_hrv"`
-	AverageBreath      float64 `json:"average_breath"`
-	RestlessPeriods    int     `json:"restless_periods"`
+	ID                 string            `json:"id"`
+	Day                string            `json:"day"`
+	Type               string            `json:"type"`
+	Period             int               `json:"period"`
+	BedtimeStart       string            `json:"bedtime_start"`
+	BedtimeEnd         string            `json:"bedtime_end"`
+	TotalSleepDuration int               `json:"total_sleep_duration"`
+	TimeInBed          int               `json:"time_in_bed"`
+	Efficiency         int               `json:"efficiency"`
+	DeepSleepDuration  int               `json:"deep_sleep_duration"`
+	LightSleepDuration int               `json:"light_sleep_duration"`
+	RemSleepDuration   int               `json:"rem_sleep_duration"`
+	AwakeTime          int               `json:"awake_time"`
+	Latency            int               `json:"latency"`
+	LowestHeartRate    int               `json:"lowest_heart_rate"`
+	AverageHeartRate   float64           `json:"average_heart_rate"`
+	AverageHRV         int               `json:"average_hrv"`
+	AverageBreath      float64           `json:"average_breath"`
+	RestlessPeriods    int               `json:"restless_periods"`
+	SleepPhase5Min     string            `json:"sleep_phase_5_min"`
+	Movement30Sec      string            `json:"movement_30_sec"`
+	HeartRate          *SampleModel      `json:"heart_rate"`
+	HRV                *SampleModel      `json:"hrv"`
+	Readiness          *ReadinessSummary `json:"readiness"`
+}
+
+// SampleModel is a regularly sampled series; nil items are gaps.
+type SampleModel struct {
+	Interval  float64    `json:"interval"`
+	Items     []*float64 `json:"items"`
+	Timestamp string     `json:"timestamp"`
+}
+
+// ReadinessSummary is the readiness attached to a single sleep period.
+type ReadinessSummary struct {
+	Score                     int                   `json:"score"`
+	TemperatureDeviation      *float64              `json:"temperature_deviation"`
+	TemperatureTrendDeviation *float64              `json:"temperature_trend_deviation"`
+	Contributors              ReadinessContributors `json:"contributors"`
 }
 
 type DailySleepResponse struct {
@@ -555,6 +1166,7 @@ type DailySleepResponse struct {
 }
 
 type DailySleepRecord struct {
+	ID           string `json:"id"`
 	Day          string `json:"day"`
 	Score        int    `json:"score"`
 	Contributors struct {
@@ -573,21 +1185,24 @@ type ReadinessResponse struct {
 }
 
 type ReadinessRecord struct {
-	Day                       string   `json:"day"`
-	Score                     int      `json:"score"`
-	TemperatureDeviation      float64  `json:"temperature_deviation"`
-	TemperatureTrendDeviation *float64 `json:"temperature_trend_deviation"`
-	Contributors              struct {
-		ActivityBalance     int  `json:"activity_balance"`
-		BodyTemperature     int  `json:"body_temperature"`
-		HRVBalance          *int `json:"hrv_balance"`
-		PreviousDayActivity int  `json:"previous_day_activity"`
-		PreviousNight       int  `json:"previous_night"`
-		RecoveryIndex       int  `json:"recovery_index"`
-		RestingHeartRate    int  `json:"resting_heart_rate"`
-		SleepBalance        *int `json:"sleep_balance"`
-		SleepRegularity     *int `json:"sleep_regularity"`
-	} `json:"contributors"`
+	ID                        string                `json:"id"`
+	Day                       string                `json:"day"`
+	Score                     int                   `json:"score"`
+	TemperatureDeviation      float64               `json:"temperature_deviation"`
+	TemperatureTrendDeviation *float64              `json:"temperature_trend_deviation"`
+	Contributors              ReadinessContributors `json:"contributors"`
+}
+
+type ReadinessContributors struct {
+	ActivityBalance     int  `json:"activity_balance"`
+	BodyTemperature     int  `json:"body_temperature"`
+	HRVBalance          *int `json:"hrv_balance"`
+	PreviousDayActivity int  `json:"previous_day_activity"`
+	PreviousNight       int  `json:"previous_night"`
+	RecoveryIndex       int  `json:"recovery_index"`
+	RestingHeartRate    int  `json:"resting_heart_rate"`
+	SleepBalance        *int `json:"sleep_balance"`
+	SleepRegularity     *int `json:"sleep_regularity"`
 }
 
 type ActivityResponse struct {
@@ -595,6 +1210,7 @@ type ActivityResponse struct {
 }
 
 type ActivityRecord struct {
+	ID                    string `json:"id"`
 	Day                   string `json:"day"`
 	Score                 int    `json:"score"`
 	Steps                 int    `json:"steps"`
@@ -624,10 +1240,11 @@ type StressResponse struct {
 }
 
 type StressRecord struct {
-	Day           string  `json:"day"`
-	StressHigh    int     `json:"stress_high"`
-	RecoveryHigh  int     `json:"recovery_high"`
-	DaytimeStress float64 `json:"day_summary"`
+	ID           string `json:"id"`
+	Day          string `json:"day"`
+	StressHigh   int    `json:"stress_high"`
+	RecoveryHigh int    `json:"recovery_high"`
+	DaySummary   string `json:"day_summary"`
 }
 
 type SpO2Response struct {
@@ -635,6 +1252,7 @@ type SpO2Response struct {
 }
 
 type SpO2Record struct {
+	ID             string `json:"id"`
 	Day            string `json:"day"`
 	SpO2Percentage struct {
 		Average float64 `json:"average"`
@@ -647,6 +1265,7 @@ type ResilienceResponse struct {
 }
 
 type ResilienceRecord struct {
+	ID           string `json:"id"`
 	Day          string `json:"day"`
 	Level        string `json:"level"`
 	Contributors struct {
@@ -660,6 +1279,7 @@ type VO2MaxResponse struct {
 }
 
 type VO2MaxRecord struct {
+	ID     string  `json:"id"`
 	Day    string  `json:"day"`
 	VO2Max float64 `json:"vo2_max"`
 }
@@ -669,6 +1289,7 @@ type WorkoutResponse struct {
 }
 
 type WorkoutRecord struct {
+	ID            string  `json:"id"`
 	Day           string  `json:"day"`
 	Activity      string  `json:"activity"`
 	Calories      float64 `json:"calories"`
@@ -682,47 +1303,48 @@ type WorkoutRecord struct {
 
 // Fetch functions
 
-func fetchSleep(date string) {
-	targetDate, _ := time.Parse("2006-01-02", date)
-	startDate := targetDate.AddDate(0, 0, -1).Format("2006-01-02")
-	endDate := targetDate.AddDate(0, 0, 1).Format("2006-01-02")
-
-	params := url.Values{}
-	params.Set("start_date", startDate)
-	params.Set("end_date", endDate)
+func fetchSleep(startDate string, endDate string, hypnogram bool) {
+	params := paddedRangeParams(startDate, endDate, 1, 1)
 
 	// Try daily_sleep first for the score
-	dailyBody, dailyErr := apiGet("/daily_sleep", params)
-	var dailyData DailySleepResponse
-	var dailySleep *DailySleepRecord
-	if dailyErr == nil {
-		json.Unmarshal(dailyBody, &dailyData)
-		for i := range dailyData.Data {
-			if dailyData.Data[i].Day == date {
-				dailySleep = &dailyData.Data[i]
-				break
-			}
-		}
+	dailyBody, dailyErr := apiGetAll("/daily_sleep", params)
+	if dailyErr != nil {
+		dailyBody = nil
 	}
 
 	// Get detailed sleep periods
-	body, err := apiGet("/sleep", params)
+	body, err := apiGetAll("/sleep", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	printSleepDays(startDate, endDate, dailyBody, body, hypnogram)
+}
+
+// printSleepDays prints each day of startDate..endDate from /daily_sleep
+// (optional, for the score) and /sleep responses.
+func printSleepDays(startDate string, endDate string, dailyBody []byte, body []byte, hypnogram bool) {
+	var dailyData DailySleepResponse
+	if dailyBody != nil {
+		json.Unmarshal(dailyBody, &dailyData)
+	}
 	var data SleepResponse
 	json.Unmarshal(body, &data)
 
-	// Collect all sleep records for this date
-	var sleepRecords []SleepRecord
-	for i := range data.Data {
-		if data.Data[i].Day == date {
-			sleepRecords = append(sleepRecords, data.Data[i])
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
 		}
+		var dailySleep *DailySleepRecord
+		if d := recordsForDay(dailyData.Data, date, func(r DailySleepRecord) string { return r.Day }); len(d) > 0 {
+			dailySleep = &d[0]
+		}
+		printSleepDay(date, dailySleep, recordsForDay(data.Data, date, func(r SleepRecord) string { return r.Day }), hypnogram)
 	}
+}
 
+func printSleepDay(date string, dailySleep *DailySleepRecord, sleepRecords []SleepRecord, hypnogram bool) {
 	if len(sleepRecords) == 0 && dailySleep == nil {
 		fmt.Println("No sleep data for", date)
 		return
@@ -778,23 +1400,17 @@ func fetchSleep(date string) {
 		fmt.Printf("Average HRV:   %d ms\n", s.AverageHRV)
 		fmt.Printf("Breath Rate:   %.1f /min\n", s.AverageBreath)
 		fmt.Printf("Restlessness:  %d periods\n", s.RestlessPeriods)
+		if hypnogram {
+			fmt.Println()
+			printHypnogram(s)
+		}
 	}
 }
 
-func fetchHRV(date string) {
-	targetDate, err := time.Parse("2006-01-02", date)
-	startDate := date
-	endDate := date
-	if err == nil {
-		startDate = targetDate.AddDate(0, 0, -1).Format("2006-01-02")
-		endDate = targetDate.AddDate(0, 0, 1).Format("2006-01-02")
-	}
-
-	params := url.Values{}
-	params.Set("start_date", startDate)
-	params.Set("end_date", endDate)
+func fetchHRV(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 1, 1)
 
-	body, err := apiGet("/sleep", params)
+	body, err := apiGetAll("/sleep", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -803,14 +1419,15 @@ func fetchHRV(date string) {
 	var data SleepResponse
 	json.Unmarshal(body, &data)
 
-	// Collect all sleep records for this date.
-	var records []SleepRecord
-	for i := range data.Data {
-		if data.Data[i].Day == date {
-			records = append(records, data.Data[i])
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
 		}
+		printHRVDay(date, recordsForDay(data.Data, date, func(r SleepRecord) string { return r.Day }))
 	}
+}
 
+func printHRVDay(date string, records []SleepRecord) {
 	if len(records) == 0 {
 		fmt.Println("No HRV data for", date)
 		return
@@ -846,37 +1463,37 @@ func fetchHRV(date string) {
 	}
 }
 
-func fetchReadiness(date string) {
-	targetDate, _ := time.Parse("2006-01-02", date)
-	startDate := targetDate.AddDate(0, 0, -1).Format("2006-01-02")
-	endDate := targetDate.AddDate(0, 0, 1).Format("2006-01-02")
+func fetchReadiness(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 1, 1)
 
-	params := url.Values{}
-	params.Set("start_date", startDate)
-	params.Set("end_date", endDate)
-
-	body, err := apiGet("/daily_readiness", params)
+	body, err := apiGetAll("/daily_readiness", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	printReadinessDays(startDate, endDate, body)
+}
 
+// printReadinessDays prints each day of startDate..endDate from a /daily_readiness
+// response.
+func printReadinessDays(startDate string, endDate string, body []byte) {
 	var data ReadinessResponse
 	json.Unmarshal(body, &data)
 
-	var r *ReadinessRecord
-	for i := range data.Data {
-		if data.Data[i].Day == date {
-			r = &data.Data[i]
-			break
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
 		}
+		records := recordsForDay(data.Data, date, func(r ReadinessRecord) string { return r.Day })
+		if len(records) == 0 {
+			fmt.Println("No readiness data for", date)
+			continue
+		}
+		printReadiness(records[0])
 	}
+}
 
-	if r == nil {
-		fmt.Println("No readiness data for", date)
-		return
-	}
-
+func printReadiness(r ReadinessRecord) {
 	c := r.Contributors
 
 	fmt.Printf("💪 Readiness - %s\n", r.Day)
@@ -902,37 +1519,37 @@ func fetchReadiness(date string) {
 	}
 }
 
-func fetchActivity(date string) {
-	targetDate, _ := time.Parse("2006-01-02", date)
-	startDate := targetDate.AddDate(0, 0, -1).Format("2006-01-02")
-	endDate := targetDate.AddDate(0, 0, 1).Format("2006-01-02")
-
-	params := url.Values{}
-	params.Set("start_date", startDate)
-	params.Set("end_date", endDate)
+func fetchActivity(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 1, 1)
 
-	body, err := apiGet("/daily_activity", params)
+	body, err := apiGetAll("/daily_activity", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	printActivityDays(startDate, endDate, body)
+}
 
+// printActivityDays prints each day of startDate..endDate from a /daily_activity
+// response.
+func printActivityDays(startDate string, endDate string, body []byte) {
 	var data ActivityResponse
 	json.Unmarshal(body, &data)
 
-	var a *ActivityRecord
-	for i := range data.Data {
-		if data.Data[i].Day == date {
-			a = &data.Data[i]
-			break
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
 		}
+		records := recordsForDay(data.Data, date, func(r ActivityRecord) string { return r.Day })
+		if len(records) == 0 {
+			fmt.Println("No activity data for", date)
+			continue
+		}
+		printActivity(records[0])
 	}
+}
 
-	if a == nil {
-		fmt.Println("No activity data for", date)
-		return
-	}
-
+func printActivity(a ActivityRecord) {
 	fmt.Printf("🏃 Activity - %s\n", a.Day)
 	fmt.Println(strings.Repeat("─", 40))
 	fmt.Printf("Score:         %d\n", a.Score)
@@ -950,79 +1567,50 @@ func fetchActivity(date string) {
 	fmt.Printf("Resting:       %s\n", formatDuration(a.RestingTime))
 }
 
-func fetchHeartRate(date string) {
-	params := url.Values{}
-	params.Set("start_date", date)
-	params.Set("end_date", date)
+func fetchStress(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 0, 0)
 
-	body, err := apiGet("/heartrate", params)
+	body, err := apiGetAll("/daily_stress", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	printStressDays(startDate, endDate, body)
+}
 
-	var data HeartRateResponse
+// printStressDays prints each day of startDate..endDate from a /daily_stress
+// response.
+func printStressDays(startDate string, endDate string, body []byte) {
+	var data StressResponse
 	json.Unmarshal(body, &data)
 
-	if len(data.Data) == 0 {
-		fmt.Println("No heart rate data for", date)
-		return
-	}
-
-	var min, max, sum int
-	min = 999
-	for _, hr := range data.Data {
-		if hr.BPM < min {
-			min = hr.BPM
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
 		}
-		if hr.BPM > max {
-			max = hr.BPM
+		records := recordsForDay(data.Data, date, func(r StressRecord) string { return r.Day })
+		if len(records) == 0 {
+			fmt.Println("No stress data for", date)
+			continue
 		}
-		sum += hr.BPM
+		printStress(records[0])
 	}
-	avg := sum / len(data.Data)
-
-	fmt.Printf("❤️  Heart Rate - %s\n", date)
-	fmt.Println(strings.Repeat("─", 40))
-	fmt.Printf("Readings:  %d\n", len(data.Data))
-	fmt.Printf("Min:       %d bpm\n", min)
-	fmt.Printf("Max:       %d bpm\n", max)
-	fmt.Printf("Average:   %d bpm\n", avg)
 }
 
-func fetchStress(date string) {
-	params := url.Values{}
-	params.Set("start_date", date)
-	params.Set("end_date", date)
-
-	body, err := apiGet("/daily_stress", params)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
-
-	var data StressResponse
-	json.Unmarshal(body, &data)
-
-	if len(data.Data) == 0 {
-		fmt.Println("No stress data for", date)
-		return
-	}
-
-	s := data.Data[0]
-
+func printStress(s StressRecord) {
 	fmt.Printf("😤 Stress - %s\n", s.Day)
 	fmt.Println(strings.Repeat("─", 40))
 	fmt.Printf("Stress High:     %d min\n", s.StressHigh)
 	fmt.Printf("Recovery High:   %d min\n", s.RecoveryHigh)
+	if s.DaySummary != "" {
+		fmt.Printf("Day Summary:     %s\n", s.DaySummary)
+	}
 }
 
-func fetchSpO2(date string) {
-	params := url.Values{}
-	params.Set("start_date", date)
-	params.Set("end_date", date)
+func fetchSpO2(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 0, 0)
 
-	body, err := apiGet("/daily_spo2", params)
+	body, err := apiGetAll("/daily_spo2", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -1031,25 +1619,30 @@ func fetchSpO2(date string) {
 	var data SpO2Response
 	json.Unmarshal(body, &data)
 
-	if len(data.Data) == 0 {
-		fmt.Println("No SpO2 data for", date)
-		return
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
+		}
+		records := recordsForDay(data.Data, date, func(r SpO2Record) string { return r.Day })
+		if len(records) == 0 {
+			fmt.Println("No SpO2 data for", date)
+			continue
+		}
+		printSpO2(records[0])
 	}
+}
 
-	s := data.Data[0]
-
+func printSpO2(s SpO2Record) {
 	fmt.Printf("🫁 Blood Oxygen - %s\n", s.Day)
 	fmt.Println(strings.Repeat("─", 40))
 	fmt.Printf("Average SpO2:    %.1f%%\n", s.SpO2Percentage.Average)
 	fmt.Printf("Breathing Index: %.2f\n", s.BreathingDisturbanceIndex)
 }
 
-func fetchResilience(date string) {
-	params := url.Values{}
-	params.Set("start_date", date)
-	params.Set("end_date", date)
+func fetchResilience(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 0, 0)
 
-	body, err := apiGet("/daily_resilience", params)
+	body, err := apiGetAll("/daily_resilience", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -1058,13 +1651,20 @@ func fetchResilience(date string) {
 	var data ResilienceResponse
 	json.Unmarshal(body, &data)
 
-	if len(data.Data) == 0 {
-		fmt.Println("No resilience data for", date)
-		return
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
+		}
+		records := recordsForDay(data.Data, date, func(r ResilienceRecord) string { return r.Day })
+		if len(records) == 0 {
+			fmt.Println("No resilience data for", date)
+			continue
+		}
+		printResilience(records[0])
 	}
+}
 
-	r := data.Data[0]
-
+func printResilience(r ResilienceRecord) {
 	fmt.Printf("🛡️  Resilience - %s\n", r.Day)
 	fmt.Println(strings.Repeat("─", 40))
 	fmt.Printf("Level:            %s\n", r.Level)
@@ -1072,12 +1672,10 @@ func fetchResilience(date string) {
 	fmt.Printf("Daytime Recovery: %.0f%%\n", r.Contributors.DaytimeRecovery*100)
 }
 
-func fetchVO2Max(date string) {
-	params := url.Values{}
-	params.Set("start_date", date)
-	params.Set("end_date", date)
+func fetchVO2Max(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 0, 0)
 
-	body, err := apiGet("/vO2_max", params)
+	body, err := apiGetAll("/vO2_max", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -1086,24 +1684,29 @@ func fetchVO2Max(date string) {
 	var data VO2MaxResponse
 	json.Unmarshal(body, &data)
 
-	if len(data.Data) == 0 {
-		fmt.Println("No VO2 max data for", date)
-		return
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
+		}
+		records := recordsForDay(data.Data, date, func(r VO2MaxRecord) string { return r.Day })
+		if len(records) == 0 {
+			fmt.Println("No VO2 max data for", date)
+			continue
+		}
+		printVO2Max(records[0])
 	}
+}
 
-	v := data.Data[0]
-
+func printVO2Max(v VO2MaxRecord) {
 	fmt.Printf("🏋️  VO2 Max - %s\n", v.Day)
 	fmt.Println(strings.Repeat("─", 40))
 	fmt.Printf("VO2 Max:  %.1f ml/kg/min\n", v.VO2Max)
 }
 
-func fetchWorkouts(date string) {
-	params := url.Values{}
-	params.Set("start_date", date)
-	params.Set("end_date", date)
+func fetchWorkouts(startDate string, endDate string) {
+	params := paddedRangeParams(startDate, endDate, 0, 0)
 
-	body, err := apiGet("/workout", params)
+	body, err := apiGetAll("/workout", params)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -1112,7 +1715,16 @@ func fetchWorkouts(date string) {
 	var data WorkoutResponse
 	json.Unmarshal(body, &data)
 
-	if len(data.Data) == 0 {
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
+		}
+		printWorkoutsDay(date, recordsForDay(data.Data, date, func(r WorkoutRecord) string { return r.Day }))
+	}
+}
+
+func printWorkoutsDay(date string, workouts []WorkoutRecord) {
+	if len(workouts) == 0 {
 		fmt.Println("No workout data for", date)
 		return
 	}
@@ -1120,7 +1732,7 @@ func fetchWorkouts(date string) {
 	fmt.Printf("🏋️  Workouts - %s\n", date)
 	fmt.Println(strings.Repeat("─", 40))
 
-	for i, w := range data.Data {
+	for i, w := range workouts {
 		if i > 0 {
 			fmt.Println()
 		}
@@ -1152,15 +1764,75 @@ func fetchAll(date string) {
 	fmt.Printf("║      OURA METRICS - %-10s       ║\n", date)
 	fmt.Printf("╚══════════════════════════════════════╝\n\n")
 
-	fetchReadiness(date)
+	// Fetch every section's data in parallel up front; the sections below
+	// then print in order from the results.
+	dayParams := paddedRangeParams(date, date, 1, 1)
+	exactParams := paddedRangeParams(date, date, 0, 0)
+	hrStart, hrEnd := dayWindow(date, date)
+	res := fetchConcurrently([]endpointRequest{
+		{Endpoint: "/rest_mode_period", Params: url.Values{"start_date": {restModeEarliestDay}, "end_date": {date}}},
+		{Endpoint: "/daily_readiness", Params: dayParams},
+		{Endpoint: "/daily_sleep", Params: dayParams},
+		{Endpoint: "/sleep", Params: dayParams},
+		{Endpoint: "/daily_activity", Params: dayParams},
+		{Endpoint: "/daily_stress", Params: exactParams},
+		{Endpoint: "/daily_cardiovascular_age", Params: exactParams},
+		{Endpoint: "/personal_info", Single: true},
+		{Endpoint: "/heartrate", Params: heartRateParams(hrStart, hrEnd)},
+	})
+	restMode, readiness, dailySleep, sleep, activity, stress, cardio, personal, hr :=
+		res[0], res[1], res[2], res[3], res[4], res[5], res[6], res[7], res[8]
+
+	// As in the single-metric commands, a failed section ends the overview.
+	body := func(r endpointResponse) []byte {
+		if r.Err != nil {
+			exitErr(r.Err)
+		}
+		return r.Body
+	}
+
+	if p := restModeOn(date, restMode); p != nil {
+		fmt.Printf("🧘 Rest mode active (since %s)\n\n", p.StartDay)
+	}
+
+	printReadinessDays(date, date, body(readiness))
 	fmt.Println()
-	fetchSleep(date)
+	if dailySleep.Err != nil {
+		dailySleep.Body = nil
+	}
+	printSleepDays(date, date, dailySleep.Body, body(sleep), false)
 	fmt.Println()
-	fetchActivity(date)
+	printActivityDays(date, date, body(activity))
 	fmt.Println()
-	fetchStress(date)
+	printStressDays(date, date, body(stress))
 	fmt.Println()
-	fetchHeartRate(date)
+	printCardioAgeDays(date, date, body(cardio), func() (int, error) {
+		return ageFromPersonalInfo(personal.Body, personal.Err)
+	})
+	fmt.Println()
+	printHeartRateWindow(windowLabel(hrStart, hrEnd), body(hr))
+}
+
+// Rest mode periods are looked up by start day and can last any length of
+// time, so the overview asks for every period from restModeEarliestDay on,
+// which predates any Oura ring data.
+const restModeEarliestDay = "2015-01-01"
+
+// restModeOn returns the rest mode period covering date, if any, given the
+// /rest_mode_period response up to date. Errors are ignored: the overview
+// should not fail because of a missing scope.
+func restModeOn(date string, periods endpointResponse) *RestModePeriodModel {
+	var resp MultiDocumentResponse[RestModePeriodModel]
+	if periods.Err != nil || json.Unmarshal(periods.Body, &resp) != nil {
+		return nil
+	}
+	// Periods don't overlap: if none found covers date, no earlier one can.
+	for i := len(resp.Data) - 1; i >= 0; i-- {
+		if resp.Data[i].Covers(date) {
+			return &resp.Data[i]
+		}
+	}
+	return nil
 }
 
 func writeJSONToStdout(v any) {
@@ -1183,91 +1855,171 @@ func paddedDateRange(date string, beforeDays int, afterDays int) (startDate stri
 	return startDate, endDate
 }
 
-func fetchEndpointsJSON(command string, date string, startDate string, endDate string, endpoints []string) {
+// paddedRangeParams builds start_date/end_date query params covering
+// startDate..endDate widened by the given number of days on each side.
+func paddedRangeParams(startDate string, endDate string, beforeDays int, afterDays int) url.Values {
+	queryStart, _ := paddedDateRange(startDate, beforeDays, 0)
+	_, queryEnd := paddedDateRange(endDate, 0, afterDays)
+
+	params := url.Values{}
+	params.Set("start_date", queryStart)
+	params.Set("end_date", queryEnd)
+	return params
+}
+
+// recordsForDay returns the records whose day matches date, in API order.
+func recordsForDay[T any](records []T, date string, dayOf func(T) string) []T {
+	var out []T
+	for _, r := range records {
+		if dayOf(r) == date {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// daysInRange lists every day from startDate to endDate inclusive.
+func daysInRange(startDate string, endDate string) []string {
+	start, err1 := time.Parse("2006-01-02", startDate)
+	end, err2 := time.Parse("2006-01-02", endDate)
+	if err1 != nil || err2 != nil || end.Before(start) {
+		return []string{startDate}
+	}
+	var days []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format("2006-01-02"))
+	}
+	return days
+}
+
+// endpointRequests builds the requests behind a metric command: day
+// collections over queryStart..queryEnd, heart rate over the
+// startDate..endDate window.
+func endpointRequests(startDate string, endDate string, queryStart string, queryEnd string, endpoints []string) []endpointRequest {
 	params := url.Values{}
-	params.Set("start_date", startDate)
-	params.Set("end_date", endDate)
+	params.Set("start_date", queryStart)
+	params.Set("end_date", queryEnd)
+
+	reqs := make([]endpointRequest, len(endpoints))
+	for i, ep := range endpoints {
+		reqs[i] = endpointRequest{Endpoint: ep, Params: params}
+		switch ep {
+		case "/heartrate":
+			// /heartrate is a time series keyed by datetime, not day.
+			reqs[i].Params = heartRateParams(dayWindow(startDate, endDate))
+		case "/personal_info":
+			// Single document, not a paginated collection.
+			reqs[i] = endpointRequest{Endpoint: ep, Single: true}
+		}
+	}
+	return reqs
+}
 
+func fetchEndpointsJSON(command string, startDate string, endDate string, queryStart string, queryEnd string, endpoints []string) {
 	out := JSONOutput{
 		Command:   command,
-		Date:      date,
-		StartDate: startDate,
-		EndDate:   endDate,
+		Date:      startDate,
+		StartDate: queryStart,
+		EndDate:   queryEnd,
 		Endpoints: make(map[string]EndpointResult, len(endpoints)),
 	}
+	if endDate != startDate {
+		out.From = startDate
+		out.To = endDate
+	}
 
-	for _, ep := range endpoints {
-		name := strings.TrimPrefix(ep, "/")
-		body, err := apiGet(ep, params)
-		if err != nil {
-			out.Endpoints[name] = EndpointResult{Error: err.Error()}
+	reqs := endpointRequests(startDate, endDate, queryStart, queryEnd, endpoints)
+	if jsonVersion == 2 {
+		writeDaysJSON(command, startDate, endDate, reqs, fetchConcurrently(reqs))
+		return
+	}
+	checkEndpointsFilter(reqs)
+	if ndjsonOutput() {
+		streamEndpoints(startDate, endDate, reqs)
+		return
+	}
+	results := fetchConcurrently(reqs)
+	if tabularOutput() {
+		writeEndpointsTable(startDate, endDate, reqs, results)
+		return
+	}
+	if templateOutput() {
+		renderEndpoints(startDate, endDate, reqs, results)
+		return
+	}
+	for i, r := range results {
+		name := strings.TrimPrefix(endpoints[i], "/")
+		if r.Err != nil {
+			out.Endpoints[name] = EndpointResult{Error: r.Err.Error()}
 			continue
 		}
+		body := r.Body
+		if filteredOutput() {
+			var err error
+			body, err = filterBody(endpointModels[reqs[i].Endpoint], body, reqs[i].Single, startDate, endDate)
+			if err != nil {
+				out.Endpoints[name] = EndpointResult{Error: err.Error()}
+				continue
+			}
+		}
 		out.Endpoints[name] = EndpointResult{Data: json.RawMessage(body)}
 	}
 
 	writeJSONToStdout(out)
 }
 
-func fetchSleepJSON(date string) {
-	startDate, endDate := paddedDateRange(date, 1, 1)
-	fetchEndpointsJSON("sleep", date, startDate, endDate, []string{"/sleep", "/daily_sleep"})
-}
-
-func fetchActivityJSON(date string) {
-	startDate, endDate := paddedDateRange(date, 1, 1)
-	fetchEndpointsJSON("activity", date, startDate, endDate, []string{"/daily_activity"})
+func fetchSleepJSON(startDate string, endDate string) {
+	queryStart, _ := paddedDateRange(startDate, 1, 0)
+	_, queryEnd := paddedDateRange(endDate, 0, 1)
+	fetchEndpointsJSON("sleep", startDate, endDate, queryStart, queryEnd, []string{"/sleep", "/daily_sleep"})
 }
 
-func fetchReadinessJSON(date string) {
-	startDate, endDate := paddedDateRange(date, 1, 1)
-	fetchEndpointsJSON("readiness", date, startDate, endDate, []string{"/daily_readiness"})
+func fetchActivityJSON(startDate string, endDate string) {
+	queryStart, _ := paddedDateRange(startDate, 1, 0)
+	_, queryEnd := paddedDateRange(endDate, 0, 1)
+	fetchEndpointsJSON("activity", startDate, endDate, queryStart, queryEnd, []string{"/daily_activity"})
 }
 
-func fetchHeartRateJSON(date string) {
-	fetchEndpointsJSON("heartrate", date, date, date, []string{"/heartrate"})
+func fetchReadinessJSON(startDate string, endDate string) {
+	queryStart, _ := paddedDateRange(startDate, 1, 0)
+	_, queryEnd := paddedDateRange(endDate, 0, 1)
+	fetchEndpointsJSON("readiness", startDate, endDate, queryStart, queryEnd, []string{"/daily_readiness"})
 }
 
-func fetchHRVJSON(date string) {
-	startDate, endDate := paddedDateRange(date, 1, 1)
+func fetchHRVJSON(startDate string, endDate string) {
+	queryStart, _ := paddedDateRange(startDate, 1, 0)
+	_, queryEnd := paddedDateRange(endDate, 0, 1)
 	// HRV is primarily exposed via sleep; readiness can include HRV-related contributors.
-	fetchEndpointsJSON("hrv", date, startDate, endDate, []string{"/sleep", "/daily_sleep", "/daily_readiness"})
+	fetchEndpointsJSON("hrv", startDate, endDate, queryStart, queryEnd, []string{"/sleep", "/daily_sleep", "/daily_readiness"})
 }
 
-func fetchStressJSON(date string) {
-	fetchEndpointsJSON("stress", date, date, date, []string{"/daily_stress"})
+func fetchStressJSON(startDate string, endDate string) {
+	fetchEndpointsJSON("stress", startDate, endDate, startDate, endDate, []string{"/daily_stress"})
 }
 
-func fetchSpO2JSON(date string) {
-	fetchEndpointsJSON("spo2", date, date, date, []string{"/daily_spo2"})
+func fetchSpO2JSON(startDate string, endDate string) {
+	fetchEndpointsJSON("spo2", startDate, endDate, startDate, endDate, []string{"/daily_spo2"})
 }
 
-func fetchResilienceJSON(date string) {
-	fetchEndpointsJSON("resilience", date, date, date, []string{"/daily_resilience"})
+func fetchResilienceJSON(startDate string, endDate string) {
+	fetchEndpointsJSON("resilience", startDate, endDate, startDate, endDate, []string{"/daily_resilience"})
 }
 
-func fetchVO2MaxJSON(date string) {
-	fetchEndpointsJSON("vo2", date, date, date, []string{"/vO2_max"})
+func fetchVO2MaxJSON(startDate string, endDate string) {
+	fetchEndpointsJSON("vo2", startDate, endDate, startDate, endDate, []string{"/vO2_max"})
 }
 
-func fetchWorkoutsJSON(date string) {
-	fetchEndpointsJSON("workout", date, date, date, []string{"/workout"})
+func fetchWorkoutsJSON(startDate string, endDate string) {
+	fetchEndpointsJSON("workout", startDate, endDate, startDate, endDate, []string{"/workout"})
 }
 
 func fetchAllJSON(date string) {
+	if templateOutput() {
+		renderDaySummary(date)
+		return
+	}
 	startDate, endDate := paddedDateRange(date, 1, 1)
-	fetchEndpointsJSON("all", date, startDate, endDate, []string{
-		"/sleep",
-		"/daily_sleep",
-		"/daily_activity",
-		"/daily_readiness",
-		"/heartrate",
-		"/daily_stress",
-		"/daily_spo2",
-		"/daily_resilience",
-		"/vO2_max",
-		"/workout",
-	})
+	fetchEndpointsJSON("all", date, date, startDate, endDate, daySummaryEndpoints)
 }
 
 func formatDuration(seconds int) string {
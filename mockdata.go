@@ -0,0 +1,381 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// generateMockData builds plausible documents for every collection over the
+// last `days` days ending at now. The same seed always yields the same data.
+func generateMockData(now time.Time, days int, seed int64) map[string][]map[string]any {
+	rng := rand.New(rand.NewSource(seed))
+	out := map[string][]map[string]any{}
+	add := func(collection string, doc map[string]any) {
+		out[collection] = append(out[collection], doc)
+	}
+	for _, c := range collections {
+		out[c.Path] = []map[string]any{}
+	}
+	out["heartrate"] = []map[string]any{}
+
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	first := today.AddDate(0, 0, -(days - 1))
+	age := 38
+
+	out["personal_info"] = []map[string]any{{
+		"id":             mockID("personal_info", "me"),
+		"email":          "mock.user@example.com",
+		"age":            age,
+		"biological_sex": "female",
+		"height":         1.70,
+		"weight":         64.5,
+	}}
+
+	out["ring_configuration"] = []map[string]any{
+		mockRing(first.AddDate(0, -8, 0), "3.1.7"),
+		mockRing(first.AddDate(0, 0, days/2), "3.2.4"),
+	}
+
+	for i := range days {
+		d := first.AddDate(0, 0, i)
+		day := d.Format(dateLayout)
+
+		bedStart := d.Add(-90*time.Minute + time.Duration(rng.Intn(60))*time.Minute)
+		inBed := 7*time.Hour + time.Duration(rng.Intn(120))*time.Minute
+		sleep := mockSleep(rng, day, bedStart, inBed)
+		add("sleep", sleep)
+
+		sleepScore := 65 + rng.Intn(30)
+		add("daily_sleep", map[string]any{
+			"id":    mockID("daily_sleep", day),
+			"day":   day,
+			"score": sleepScore,
+			"contributors": map[string]any{
+				"deep_sleep":  60 + rng.Intn(40),
+				"efficiency":  70 + rng.Intn(30),
+				"latency":     60 + rng.Intn(40),
+				"rem_sleep":   60 + rng.Intn(40),
+				"restfulness": 50 + rng.Intn(50),
+				"timing":      60 + rng.Intn(40),
+				"total_sleep": 60 + rng.Intn(40),
+			},
+			"timestamp": d.Format(time.RFC3339),
+		})
+
+		readiness := sleep["readiness"].(map[string]any)
+		add("daily_readiness", map[string]any{
+			"id":                          mockID("daily_readiness", day),
+			"day":                         day,
+			"score":                       readiness["score"],
+			"temperature_deviation":       readiness["temperature_deviation"],
+			"temperature_trend_deviation": readiness["temperature_trend_deviation"],
+			"contributors":                readiness["contributors"],
+			"timestamp":                   d.Format(time.RFC3339),
+		})
+
+		steps := 3000 + rng.Intn(12000)
+		active := steps/25 + rng.Intn(150)
+		add("daily_activity", map[string]any{
+			"id":                          mockID("daily_activity", day),
+			"day":                         day,
+			"score":                       60 + rng.Intn(40),
+			"steps":                       steps,
+			"active_calories":             active,
+			"total_calories":              1700 + active,
+			"target_calories":             450,
+			"equivalent_walking_distance": steps * 3 / 4,
+			"high_activity_time":          rng.Intn(40) * 60,
+			"medium_activity_time":        (20 + rng.Intn(60)) * 60,
+			"low_activity_time":           (120 + rng.Intn(180)) * 60,
+			"sedentary_time":              (400 + rng.Intn(200)) * 60,
+			"resting_time":                int(inBed.Seconds()),
+			"timestamp":                   d.Format(time.RFC3339),
+		})
+
+		add("daily_spo2", map[string]any{
+			"id":                          mockID("daily_spo2", day),
+			"day":                         day,
+			"spo2_percentage":             map[string]any{"average": 95 + rng.Float64()*3},
+			"breathing_disturbance_index": rng.Intn(15),
+		})
+
+		add("daily_stress", map[string]any{
+			"id":            mockID("daily_stress", day),
+			"day":           day,
+			"stress_high":   rng.Intn(180) * 60,
+			"recovery_high": rng.Intn(240) * 60,
+			"day_summary":   []string{"restored", "normal", "stressful"}[rng.Intn(3)],
+		})
+
+		add("daily_resilience", map[string]any{
+			"id":    mockID("daily_resilience", day),
+			"day":   day,
+			"level": []string{"limited", "adequate", "solid", "strong", "exceptional"}[rng.Intn(5)],
+			"contributors": map[string]any{
+				"sleep_recovery":   50 + rng.Float64()*50,
+				"daytime_recovery": 30 + rng.Float64()*60,
+				"stress":           30 + rng.Float64()*60,
+			},
+		})
+
+		vascular := age - 3 + rng.Intn(7)
+		add("daily_cardiovascular_age", map[string]any{
+			"id":           mockID("daily_cardiovascular_age", day),
+			"day":          day,
+			"vascular_age": vascular,
+		})
+
+		_, offset := d.Zone()
+		add("sleep_time", map[string]any{
+			"id":  mockID("sleep_time", day),
+			"day": day,
+			"optimal_bedtime": map[string]any{
+				"day_tz":       offset,
+				"start_offset": -7200 + rng.Intn(4)*900,
+				"end_offset":   -3600 + rng.Intn(4)*900,
+			},
+			"recommendation": "follow_optimal_bedtime",
+			"status":         "optimal_found",
+		})
+
+		if i%7 == 0 {
+			add("vO2_max", map[string]any{
+				"id":        mockID("vO2_max", day),
+				"day":       day,
+				"vo2_max":   38 + rng.Float64()*6,
+				"timestamp": d.Format(time.RFC3339),
+			})
+		}
+
+		var workoutStart, workoutEnd time.Time
+		if i%2 == 1 {
+			workoutStart = d.Add(17*time.Hour + time.Duration(rng.Intn(60))*time.Minute)
+			workoutEnd = workoutStart.Add(time.Duration(30+rng.Intn(60)) * time.Minute)
+			activity := []string{"running", "cycling", "walking", "strength_training"}[rng.Intn(4)]
+			add("workout", map[string]any{
+				"id":             mockID("workout", day),
+				"day":            day,
+				"activity":       activity,
+				"calories":       200 + rng.Float64()*400,
+				"distance":       2000 + rng.Float64()*8000,
+				"start_datetime": workoutStart.Format(time.RFC3339),
+				"end_datetime":   workoutEnd.Format(time.RFC3339),
+				"intensity":      []string{"easy", "moderate", "hard"}[rng.Intn(3)],
+				"label":          nil,
+				"source":         "autodetected",
+			})
+		}
+
+		if i%3 == 0 {
+			start := d.Add(12*time.Hour + 30*time.Minute)
+			add("session", map[string]any{
+				"id":                     mockID("session", day),
+				"day":                    day,
+				"start_datetime":         start.Format(time.RFC3339),
+				"end_datetime":           start.Add(10 * time.Minute).Format(time.RFC3339),
+				"type":                   []string{"breathing", "meditation", "relaxation"}[rng.Intn(3)],
+				"heart_rate":             mockSamples(rng, start, 5, 2, 55, 70),
+				"heart_rate_variability": mockSamples(rng, start, 5, 2, 40, 80),
+				"mood":                   []string{"same", "good", "great"}[rng.Intn(3)],
+				"motion_count":           mockSamples(rng, start, 5, 2, 0, 3),
+			})
+		}
+
+		if i%4 == 2 {
+			ts := d.Add(20 * time.Hour)
+			add("tag", map[string]any{
+				"id":        mockID("tag", day),
+				"day":       day,
+				"timestamp": ts.Format(time.RFC3339),
+				"text":      "Late dinner",
+				"tags":      []string{"tag_generic_late_meal"},
+			})
+			add("enhanced_tag", map[string]any{
+				"id":            mockID("enhanced_tag", day),
+				"tag_type_code": "tag_generic_caffeine",
+				"start_time":    d.Add(15 * time.Hour).Format(time.RFC3339),
+				"end_time":      nil,
+				"start_day":     day,
+				"end_day":       nil,
+				"comment":       "Afternoon coffee",
+				"custom_name":   nil,
+			})
+		}
+
+		out["heartrate"] = append(out["heartrate"], mockHeartRateDay(rng, d, now, sleep, workoutStart, workoutEnd)...)
+	}
+
+	if days >= 10 {
+		start := first.AddDate(0, 0, days-10)
+		end := start.AddDate(0, 0, 3)
+		add("rest_mode_period", map[string]any{
+			"id":         mockID("rest_mode_period", start.Format(dateLayout)),
+			"start_day":  start.Format(dateLayout),
+			"end_day":    end.Format(dateLayout),
+			"start_time": start.Add(9 * time.Hour).Format(time.RFC3339),
+			"end_time":   end.Add(8 * time.Hour).Format(time.RFC3339),
+			"episodes": []map[string]any{{
+				"tags":      []string{"tag_generic_sickness"},
+				"timestamp": start.Add(9 * time.Hour).Format(time.RFC3339),
+			}},
+		})
+	}
+
+	return out
+}
+
+func mockSleep(rng *rand.Rand, day string, start time.Time, inBed time.Duration) map[string]any {
+	slots := int(inBed / (5 * time.Minute))
+	var phases strings.Builder
+	stage := byte('4')
+	for i := range slots {
+		if i == 0 || rng.Intn(6) == 0 {
+			// Deep sleep is front-loaded, REM comes later in the night.
+			switch r := rng.Intn(10); {
+			case r == 0:
+				stage = '4'
+			case r < 4 && i < slots/2:
+				stage = '1'
+			case r < 4:
+				stage = '3'
+			default:
+				stage = '2'
+			}
+		}
+		phases.WriteByte(stage)
+	}
+	var movement strings.Builder
+	for range slots * 10 {
+		movement.WriteByte(byte('1' + rng.Intn(10)/7))
+	}
+
+	var deep, light, rem, awake int
+	for _, c := range phases.String() {
+		switch c {
+		case '1':
+			deep += 300
+		case '2':
+			light += 300
+		case '3':
+			rem += 300
+		case '4':
+			awake += 300
+		}
+	}
+	total := deep + light + rem
+	lowest := 48 + rng.Intn(8)
+	tempDev := -0.3 + rng.Float64()*0.6
+	trendDev := -0.2 + rng.Float64()*0.4
+	hrvBalance := 60 + rng.Intn(40)
+	sleepBalance := 60 + rng.Intn(40)
+	regularity := 60 + rng.Intn(40)
+
+	return map[string]any{
+		"id":                   mockID("sleep", day),
+		"day":                  day,
+		"type":                 "long_sleep",
+		"period":               0,
+		"bedtime_start":        start.Format(time.RFC3339),
+		"bedtime_end":          start.Add(inBed).Format(time.RFC3339),
+		"total_sleep_duration": total,
+		"time_in_bed":          int(inBed.Seconds()),
+		"efficiency":           total * 100 / int(inBed.Seconds()),
+		"deep_sleep_duration":  deep,
+		"light_sleep_duration": light,
+		"rem_sleep_duration":   rem,
+		"awake_time":           awake,
+		"latency":              300 + rng.Intn(900),
+		"lowest_heart_rate":    lowest,
+		"average_heart_rate":   float64(lowest) + 5 + rng.Float64()*4,
+		"average_hrv":          35 + rng.Intn(40),
+		"average_breath":       13 + rng.Float64()*3,
+		"restless_periods":     100 + rng.Intn(200),
+		"sleep_phase_5_min":    phases.String(),
+		"movement_30_sec":      movement.String(),
+		"heart_rate":           mockSamples(rng, start, 300, slots, lowest, lowest+15),
+		"hrv":                  mockSamples(rng, start, 300, slots, 25, 80),
+		"readiness": map[string]any{
+			"score":                       60 + rng.Intn(35),
+			"temperature_deviation":       tempDev,
+			"temperature_trend_deviation": trendDev,
+			"contributors": map[string]any{
+				"activity_balance":      60 + rng.Intn(40),
+				"body_temperature":      80 + rng.Intn(20),
+				"hrv_balance":           hrvBalance,
+				"previous_day_activity": 60 + rng.Intn(40),
+				"previous_night":        60 + rng.Intn(40),
+				"recovery_index":        60 + rng.Intn(40),
+				"resting_heart_rate":    60 + rng.Intn(40),
+				"sleep_balance":         sleepBalance,
+				"sleep_regularity":      regularity,
+			},
+		},
+	}
+}
+
+// mockSamples returns a SampleModel-shaped object with occasional gaps.
+func mockSamples(rng *rand.Rand, start time.Time, interval, n, lo, hi int) map[string]any {
+	items := make([]any, n)
+	for i := range items {
+		if rng.Intn(20) == 0 {
+			continue
+		}
+		items[i] = lo + rng.Intn(hi-lo+1)
+	}
+	return map[string]any{
+		"interval":  interval,
+		"items":     items,
+		"timestamp": start.Format(time.RFC3339),
+	}
+}
+
+// mockHeartRateDay returns 5-minute readings for one calendar day up to now,
+// tagged sleep during the night, workout during a workout and awake otherwise.
+func mockHeartRateDay(rng *rand.Rand, d, now time.Time, sleep map[string]any, workoutStart, workoutEnd time.Time) []map[string]any {
+	bedStart, _ := time.Parse(time.RFC3339, sleep["bedtime_start"].(string))
+	bedEnd, _ := time.Parse(time.RFC3339, sleep["bedtime_end"].(string))
+	lowest := sleep["lowest_heart_rate"].(int)
+
+	var out []map[string]any
+	for t := d; t.Before(d.AddDate(0, 0, 1)) && !t.After(now); t = t.Add(5 * time.Minute) {
+		source, bpm := "awake", 65+rng.Intn(25)
+		switch {
+		case !t.Before(bedStart) && t.Before(bedEnd):
+			source, bpm = "sleep", lowest+rng.Intn(12)
+		case !workoutStart.IsZero() && !t.Before(workoutStart) && t.Before(workoutEnd):
+			source, bpm = "workout", 120+rng.Intn(50)
+		case t.Hour() >= 22 || t.Hour() < 7:
+			source, bpm = "rest", 55+rng.Intn(12)
+		}
+		out = append(out, map[string]any{
+			"bpm":       bpm,
+			"source":    source,
+			"timestamp": t.UTC().Format(time.RFC3339),
+		})
+	}
+	return out
+}
+
+func mockRing(setUp time.Time, firmware string) map[string]any {
+	return map[string]any{
+		"id":               mockID("ring_configuration", firmware),
+		"color":            "stealth_black",
+		"design":           "heritage",
+		"firmware_version": firmware,
+		"hardware_type":    "gen3",
+		"set_up_at":        setUp.UTC().Format(time.RFC3339),
+		"size":             8,
+	}
+}
+
+// mockID derives a stable UUID-shaped document id from its collection and key.
+func mockID(collection, key string) string {
+	h := fnv.New128a()
+	h.Write([]byte(collection + "/" + key))
+	b := h.Sum(nil)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
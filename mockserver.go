@@ -0,0 +1,548 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mockServer serves the routes from refs/openapi-1.27.json from in-memory
+// documents so the CLI can run end to end without the real API.
+type mockServer struct {
+	pageSize int
+	// docs holds each collection's documents sorted by their day/time key.
+	docs         map[string][]map[string]any
+	personalInfo map[string]any
+
+	mu       sync.Mutex
+	webhooks map[string]WebhookSubscription
+}
+
+// mockDayKeys is the field a collection is filtered on by start_date/end_date.
+var mockDayKeys = map[string]string{
+	"enhanced_tag":     "start_day",
+	"rest_mode_period": "start_day",
+}
+
+func handleMockServer(args []string, opts Options) {
+	if opts.Help {
+		printMockServerUsage()
+		return
+	}
+	flags, pos, err := parseLongFlags(args)
+	if err != nil {
+		exitErr(err)
+	}
+	if len(pos) != 0 {
+		printMockServerUsage()
+		os.Exit(1)
+	}
+
+	port := firstFlag(flags, "port")
+	if port == "" {
+		port = "9999"
+	}
+	pageSize, err := mockIntFlag(flags, "page-size", 50)
+	if err != nil {
+		exitErr(err)
+	}
+	days, err := mockIntFlag(flags, "days", 30)
+	if err != nil {
+		exitErr(err)
+	}
+	seed, err := mockIntFlag(flags, "seed", 1)
+	if err != nil {
+		exitErr(err)
+	}
+
+	s := newMockServer(time.Now(), days, int64(seed), pageSize)
+	if dir := firstFlag(flags, "fixtures"); dir != "" {
+		if err := s.loadFixtures(dir); err != nil {
+			exitErr(err)
+		}
+	}
+
+	addr := "localhost:" + port
+	fmt.Fprintf(os.Stderr, "Mock Oura API listening on http://%s\n", addr)
+	fmt.Fprintf(os.Stderr, "Use it with: OURA_BASE_URL=http://%s oura auth\n", addr)
+	if err := http.ListenAndServe(addr, s.routes()); err != nil {
+		exitErr(err)
+	}
+}
+
+// newMockServer generates days of documents ending at now, served pageSize
+// documents per page.
+func newMockServer(now time.Time, days int, seed int64, pageSize int) *mockServer {
+	s := &mockServer{
+		pageSize: pageSize,
+		docs:     generateMockData(now, days, seed),
+		webhooks: make(map[string]WebhookSubscription),
+	}
+	s.personalInfo = s.docs["personal_info"][0]
+	delete(s.docs, "personal_info")
+	return s
+}
+
+func mockIntFlag(flags map[string]string, name string, def int) (int, error) {
+	v := firstFlag(flags, name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid --%s: %q", name, v)
+	}
+	return n, nil
+}
+
+// loadFixtures replaces generated documents with <dir>/<collection>.json.
+// A fixture is either a JSON array or a {"data": [...]} response;
+// personal_info.json holds a single object.
+func (s *mockServer) loadFixtures(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+
+		if name == "personal_info" {
+			if err := json.Unmarshal(data, &s.personalInfo); err != nil {
+				return fmt.Errorf("%s: %w", e.Name(), err)
+			}
+			continue
+		}
+		if _, known := s.docs[name]; !known {
+			fmt.Fprintf(os.Stderr, "Skipping fixture %s: unknown collection\n", e.Name())
+			continue
+		}
+
+		var docs []map[string]any
+		if err := json.Unmarshal(data, &docs); err != nil {
+			var resp MultiDocumentResponse[map[string]any]
+			if err2 := json.Unmarshal(data, &resp); err2 != nil {
+				return fmt.Errorf("%s: %w", e.Name(), err)
+			}
+			docs = resp.Data
+		}
+		for i, d := range docs {
+			if _, ok := d["id"]; !ok && name != "heartrate" {
+				d["id"] = mockID(name, strconv.Itoa(i))
+			}
+		}
+		sort.SliceStable(docs, func(i, j int) bool {
+			return mockSortKey(name, docs[i]) < mockSortKey(name, docs[j])
+		})
+		s.docs[name] = docs
+		fmt.Fprintf(os.Stderr, "Loaded %d %s documents from %s\n", len(docs), name, e.Name())
+	}
+	return nil
+}
+
+func (s *mockServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /oauth/authorize", s.handleAuthorize)
+	mux.HandleFunc("POST /oauth/token", s.handleToken)
+
+	for _, prefix := range []string{"/v2/usercollection", "/v2/sandbox/usercollection"} {
+		mux.HandleFunc("GET "+prefix+"/{collection}", s.requireBearer(s.handleList))
+		mux.HandleFunc("GET "+prefix+"/{collection}/{id}", s.requireBearer(s.handleGet))
+	}
+	mux.HandleFunc("GET /v2/usercollection/personal_info", s.requireBearer(func(w http.ResponseWriter, r *http.Request) {
+		mockWriteJSON(w, http.StatusOK, s.personalInfo)
+	}))
+
+	mux.HandleFunc("GET /v2/webhook/subscription", s.requireClient(s.handleWebhookList))
+	mux.HandleFunc("POST /v2/webhook/subscription", s.requireClient(s.handleWebhookCreate))
+	mux.HandleFunc("GET /v2/webhook/subscription/{id}", s.requireClient(s.handleWebhookGet))
+	mux.HandleFunc("PUT /v2/webhook/subscription/{id}", s.requireClient(s.handleWebhookUpdate))
+	mux.HandleFunc("DELETE /v2/webhook/subscription/{id}", s.requireClient(s.handleWebhookDelete))
+	mux.HandleFunc("PUT /v2/webhook/subscription/renew/{id}", s.requireClient(s.handleWebhookRenew))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &mockStatusRecorder{ResponseWriter: w, status: http.StatusOK}
+		mux.ServeHTTP(rec, r)
+		fmt.Fprintf(os.Stderr, "%s %s %s -> %d\n", time.Now().Format("15:04:05"), r.Method, r.URL.RequestURI(), rec.status)
+	})
+}
+
+type mockStatusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *mockStatusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// OAuth: any client is accepted and the callback gets a fixed code.
+func (s *mockServer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirect := q.Get("redirect_uri")
+	if redirect == "" {
+		mockError(w, http.StatusBadRequest, "missing redirect_uri")
+		return
+	}
+	sep := "?"
+	if strings.Contains(redirect, "?") {
+		sep = "&"
+	}
+	cb := url.Values{"code": {"mock-code"}, "state": {q.Get("state")}}
+	http.Redirect(w, r, redirect+sep+cb.Encode(), http.StatusFound)
+}
+
+func (s *mockServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code", "refresh_token":
+	default:
+		mockWriteJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	mockWriteJSON(w, http.StatusOK, TokenResponse{
+		AccessToken:  "mock-access-" + mockRandomHex(8),
+		RefreshToken: "mock-refresh-" + mockRandomHex(8),
+		ExpiresIn:    86400,
+		TokenType:    "bearer",
+	})
+}
+
+func (s *mockServer) requireBearer(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			mockError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *mockServer) requireClient(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-client-id") == "" || r.Header.Get("x-client-secret") == "" {
+			mockError(w, http.StatusForbidden, "missing x-client-id/x-client-secret")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *mockServer) handleList(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("collection")
+	docs, ok := s.docs[name]
+	if !ok {
+		mockError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	q := r.URL.Query()
+	var lo, hi string
+	var err error
+	switch {
+	case name == "heartrate":
+		lo, hi, err = mockDatetimeRange(q.Get("start_datetime"), q.Get("end_datetime"))
+	case name != "ring_configuration":
+		lo, hi, err = mockDateRange(q.Get("start_date"), q.Get("end_date"))
+	}
+	if err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matched := make([]map[string]any, 0, len(docs))
+	for _, d := range docs {
+		if lo == "" {
+			matched = append(matched, d)
+			continue
+		}
+		if k := mockSortKey(name, d); k >= lo && k <= hi {
+			matched = append(matched, d)
+		}
+	}
+
+	offset := 0
+	if tok := q.Get("next_token"); tok != "" {
+		offset, err = mockDecodeToken(tok)
+		if err != nil || offset > len(matched) {
+			mockError(w, http.StatusBadRequest, "invalid next_token")
+			return
+		}
+	}
+	end := min(offset+s.pageSize, len(matched))
+	resp := MultiDocumentResponse[map[string]any]{Data: matched[offset:end]}
+	if end < len(matched) {
+		resp.NextToken = mockEncodeToken(end)
+	}
+	mockWriteJSON(w, http.StatusOK, resp)
+}
+
+func (s *mockServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, d := range s.docs[r.PathValue("collection")] {
+		if d["id"] == id {
+			mockWriteJSON(w, http.StatusOK, d)
+			return
+		}
+	}
+	mockError(w, http.StatusNotFound, "Document not found")
+}
+
+// mockDateRange mirrors the API defaults: end_date is today and start_date
+// is the day before end_date. Both bounds are inclusive.
+func mockDateRange(start, end string) (string, string, error) {
+	if end == "" {
+		end = time.Now().Format(dateLayout)
+	}
+	e, err := time.Parse(dateLayout, end)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid end_date: %q", end)
+	}
+	if start == "" {
+		start = e.AddDate(0, 0, -1).Format(dateLayout)
+	}
+	if _, err := time.Parse(dateLayout, start); err != nil {
+		return "", "", fmt.Errorf("invalid start_date: %q", start)
+	}
+	return start, end, nil
+}
+
+// mockDatetimeRange returns UTC RFC3339 bounds so they compare as strings
+// against the UTC heart-rate timestamps.
+func mockDatetimeRange(start, end string) (string, string, error) {
+	e := time.Now()
+	if end != "" {
+		t, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid end_datetime: %q", end)
+		}
+		e = t
+	}
+	s := e.Add(-24 * time.Hour)
+	if start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid start_datetime: %q", start)
+		}
+		s = t
+	}
+	return s.UTC().Format(time.RFC3339), e.UTC().Format(time.RFC3339), nil
+}
+
+func mockSortKey(collection string, d map[string]any) string {
+	switch collection {
+	case "heartrate":
+		if ts, ok := d["timestamp"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				return t.UTC().Format(time.RFC3339)
+			}
+			return ts
+		}
+	case "ring_configuration":
+		v, _ := d["set_up_at"].(string)
+		return v
+	}
+	key := mockDayKeys[collection]
+	if key == "" {
+		key = "day"
+	}
+	v, _ := d[key].(string)
+	return v
+}
+
+func mockEncodeToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func mockDecodeToken(tok string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(tok)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := strings.CutPrefix(string(b), "offset:")
+	if !ok {
+		return 0, fmt.Errorf("invalid token")
+	}
+	return strconv.Atoi(n)
+}
+
+func (s *mockServer) handleWebhookList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subs := make([]WebhookSubscription, 0, len(s.webhooks))
+	for _, sub := range s.webhooks {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	mockWriteJSON(w, http.StatusOK, subs)
+}
+
+func (s *mockServer) handleWebhookCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		mockError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if req.CallbackURL == "" || req.VerificationToken == "" {
+		mockError(w, http.StatusUnprocessableEntity, "callback_url and verification_token are required")
+		return
+	}
+	if err := mockValidateWebhook(req.EventType, req.DataType); err != nil {
+		mockError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	sub := WebhookSubscription{
+		ID:             mockID("webhook", mockRandomHex(8)),
+		CallbackURL:    req.CallbackURL,
+		EventType:      req.EventType,
+		DataType:       req.DataType,
+		ExpirationTime: mockWebhookExpiry(),
+	}
+	s.mu.Lock()
+	s.webhooks[sub.ID] = sub
+	s.mu.Unlock()
+	mockWriteJSON(w, http.StatusCreated, sub)
+}
+
+func (s *mockServer) handleWebhookGet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		mockError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+	mockWriteJSON(w, http.StatusOK, sub)
+}
+
+func (s *mockServer) handleWebhookUpdate(w http.ResponseWriter, r *http.Request) {
+	var req UpdateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		mockError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if req.VerificationToken == "" {
+		mockError(w, http.StatusUnprocessableEntity, "verification_token is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		mockError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+	if req.CallbackURL != nil {
+		sub.CallbackURL = *req.CallbackURL
+	}
+	if req.EventType != nil {
+		sub.EventType = *req.EventType
+	}
+	if req.DataType != nil {
+		sub.DataType = *req.DataType
+	}
+	if err := mockValidateWebhook(sub.EventType, sub.DataType); err != nil {
+		mockError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	s.webhooks[sub.ID] = sub
+	mockWriteJSON(w, http.StatusOK, sub)
+}
+
+func (s *mockServer) handleWebhookDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	if _, ok := s.webhooks[id]; !ok {
+		mockError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+	delete(s.webhooks, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *mockServer) handleWebhookRenew(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		mockError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+	sub.ExpirationTime = mockWebhookExpiry()
+	s.webhooks[sub.ID] = sub
+	mockWriteJSON(w, http.StatusOK, sub)
+}
+
+func mockValidateWebhook(eventType, dataType string) error {
+	if err := validateEnum("event_type", eventType, webhookOperations); err != nil {
+		return err
+	}
+	return validateEnum("data_type", dataType, webhookDataTypes)
+}
+
+func mockWebhookExpiry() string {
+	return time.Now().UTC().AddDate(0, 0, 90).Format(time.RFC3339)
+}
+
+func mockRandomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func mockWriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// mockError responds in the API's HTTPValidationError shape.
+func mockError(w http.ResponseWriter, status int, msg string) {
+	mockWriteJSON(w, status, map[string]string{"detail": msg})
+}
+
+func printMockServerUsage() {
+	fmt.Println(`Usage: oura mock-server [--port <n>] [--fixtures <dir>] [--page-size <n>] [--days <n>] [--seed <n>]
+
+Serve a local stand-in for the Oura API: every usercollection route (live
+and sandbox), heartrate, personal_info, webhook subscriptions and the OAuth
+authorize/token endpoints. Point the CLI at it with OURA_BASE_URL.
+
+Flags:
+  --port <n>        Listen port on localhost (default: 9999)
+  --fixtures <dir>  Serve <dir>/<collection>.json instead of generated data
+                    (e.g. daily_sleep.json, heartrate.json, personal_info.json)
+  --page-size <n>   Documents per page before next_token (default: 50)
+  --days <n>        Days of generated data ending today (default: 30)
+  --seed <n>        Seed for generated data (default: 1)
+
+Example:
+  oura mock-server --port 9999 &
+  OURA_BASE_URL=http://localhost:9999 oura auth
+  OURA_BASE_URL=http://localhost:9999 oura today`)
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMain lets the end-to-end tests run this test binary as the oura
+// command itself.
+func TestMain(m *testing.M) {
+	if os.Getenv("OURA_TEST_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// mockCLI runs oura against an in-process mock server and records the
+// request URIs it receives.
+type mockCLI struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newMockCLI(t *testing.T, pageSize int) *mockCLI {
+	c := &mockCLI{t: t}
+	routes := newMockServer(time.Now(), 30, 1, pageSize).routes()
+	c.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		c.requests = append(c.requests, r.URL.RequestURI())
+		c.mu.Unlock()
+		routes.ServeHTTP(w, r)
+	}))
+	t.Cleanup(c.srv.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("OURA_BASE_URL", c.srv.URL)
+	t.Setenv("OURA_SANDBOX", "")
+
+	config := []byte(`{"client_id": "test-id", "client_secret": "test-secret"}`)
+	if err := os.WriteFile(getConfigDir()+"/config.json", config, 0600); err != nil {
+		t.Fatal(err)
+	}
+	c.saveToken(time.Now().Add(time.Hour))
+	return c
+}
+
+// saveToken stores a test token for the mock server that expires at
+// expiresAt.
+func (c *mockCLI) saveToken(expiresAt time.Time) {
+	c.t.Helper()
+	prev := apiHost
+	apiHost = c.srv.URL
+	defer func() { apiHost = prev }()
+	if err := saveToken(&StoredToken{AccessToken: "test-access", RefreshToken: "test-refresh", ExpiresAt: expiresAt}); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// run executes oura with args and returns its stdout, failing the test if
+// the command exits non-zero.
+func (c *mockCLI) run(args ...string) string {
+	c.t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "OURA_TEST_MAIN=1")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		c.t.Fatalf("oura %s: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return stdout.String()
+}
+
+// requested returns the recorded request URIs containing substr and resets
+// the record.
+func (c *mockCLI) requested(substr string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []string
+	for _, r := range c.requests {
+		if strings.Contains(r, substr) {
+			out = append(out, r)
+		}
+	}
+	c.requests = nil
+	return out
+}
+
+func decodeJSON[T any](t *testing.T, s string) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decoding %q: %v", s, err)
+	}
+	return v
+}
+
+func TestMockToday(t *testing.T) {
+	c := newMockCLI(t, 50)
+
+	text := c.run("today")
+	for _, want := range []string{"OURA METRICS", "Readiness", "Sleep", "Activity"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("today output lacks %q:\n%s", want, text)
+		}
+	}
+	if reqs := c.requested("/rest_mode_period"); len(reqs) != 1 {
+		t.Errorf("today made %d rest mode requests, want 1: %v", len(reqs), reqs)
+	}
+
+	out := decodeJSON[JSONOutput](t, c.run("today", "--json"))
+	today := time.Now().Format(dateLayout)
+	if out.Date != today {
+		t.Errorf("date = %q, want %q", out.Date, today)
+	}
+	for _, ep := range []string{"daily_readiness", "daily_sleep", "sleep", "daily_activity", "heartrate"} {
+		res, ok := out.Endpoints[ep]
+		if !ok || res.Error != "" || len(res.Data) == 0 {
+			t.Errorf("endpoint %s: %+v", ep, res)
+		}
+	}
+}
+
+func TestMockSleepRange(t *testing.T) {
+	c := newMockCLI(t, 50)
+
+	out := decodeJSON[DaysOutput](t, c.run("sleep", "last-week", "--json=v2"))
+	if len(out.Days) != 7 {
+		t.Fatalf("got %d days, want 7", len(out.Days))
+	}
+	for _, d := range out.Days {
+		if d.DailySleep == nil || len(d.Sleep) == 0 {
+			t.Errorf("%s: missing sleep data: %+v", d.Day, d)
+		}
+	}
+}
+
+func TestMockListPagination(t *testing.T) {
+	c := newMockCLI(t, 10)
+
+	all := decodeJSON[MultiDocumentResponse[DailySleepRecord]](t, c.run("daily_sleep", "list", "--start-date", "-29d", "--json"))
+	if len(all.Data) != 30 {
+		t.Errorf("got %d records, want 30", len(all.Data))
+	}
+	if all.NextToken != "" {
+		t.Errorf("next_token = %q after following every page", all.NextToken)
+	}
+	if pages := c.requested("/daily_sleep"); len(pages) != 3 {
+		t.Errorf("fetched %d pages, want 3: %v", len(pages), pages)
+	}
+
+	first := decodeJSON[MultiDocumentResponse[DailySleepRecord]](t, c.run("daily_sleep", "list", "--start-date", "-29d", "--max-pages", "1", "--json"))
+	if len(first.Data) != 10 || first.NextToken == "" {
+		t.Fatalf("--max-pages 1: got %d records, next_token %q", len(first.Data), first.NextToken)
+	}
+	rest := decodeJSON[MultiDocumentResponse[DailySleepRecord]](t, c.run("daily_sleep", "list", "--start-date", "-29d", "--next-token", first.NextToken, "--json"))
+	if len(rest.Data) != 20 || rest.Data[0].Day <= first.Data[9].Day {
+		t.Errorf("--next-token: got %d records starting %s", len(rest.Data), rest.Data[0].Day)
+	}
+}
+
+func TestMockWebhookList(t *testing.T) {
+	c := newMockCLI(t, 50)
+
+	if out := decodeJSON[[]WebhookSubscription](t, c.run("webhook", "list", "--json")); len(out) != 0 {
+		t.Fatalf("new mock server has %d subscriptions", len(out))
+	}
+	created := decodeJSON[WebhookSubscription](t, c.run("webhook", "create",
+		"--callback-url", "https://example.com/hook", "--verification-token", "secret",
+		"--event-type", "create", "--data-type", "daily_sleep", "--json"))
+
+	list := decodeJSON[[]WebhookSubscription](t, c.run("webhook", "list", "--json"))
+	if len(list) != 1 || list[0].ID != created.ID || list[0].DataType != "daily_sleep" {
+		t.Errorf("webhook list = %+v, want %s", list, created.ID)
+	}
+	if text := c.run("webhook", "list"); !strings.Contains(text, created.ID) {
+		t.Errorf("webhook list lacks %s:\n%s", created.ID, text)
+	}
+}
+
+func TestMockSandbox(t *testing.T) {
+	c := newMockCLI(t, 50)
+
+	c.run("tag", "list", "--no-cache")
+	if reqs := c.requested("/v2/sandbox/"); len(reqs) != 0 {
+		t.Errorf("sandbox requests without --sandbox: %v", reqs)
+	}
+
+	c.run("daily_sleep", "list", "--sandbox", "--no-cache")
+	if reqs := c.requested("/v2/sandbox/usercollection/daily_sleep"); len(reqs) != 1 {
+		t.Errorf("--sandbox: got %d sandbox requests, want 1", len(reqs))
+	}
+
+	t.Setenv("OURA_SANDBOX", "1")
+	c.run("daily_sleep", "list", "--no-cache")
+	if reqs := c.requested("/v2/sandbox/usercollection/daily_sleep"); len(reqs) != 1 {
+		t.Errorf("OURA_SANDBOX=1: got %d sandbox requests, want 1", len(reqs))
+	}
+}
+
+func TestMockAPISkipsCache(t *testing.T) {
+	c := newMockCLI(t, 50)
+
+	for range 2 {
+		c.run("api", "/daily_sleep", "--param", "start_date=2026-01-01", "--param", "end_date=2026-01-07")
+	}
+	if reqs := c.requested("/daily_sleep"); len(reqs) != 2 {
+		t.Errorf("oura api sent %d requests for 2 calls, want 2: %v", len(reqs), reqs)
+	}
+}
+
+func TestMockOfflineExpiredToken(t *testing.T) {
+	c := newMockCLI(t, 50)
+	c.run("today")
+
+	c.saveToken(time.Now().Add(-time.Hour))
+	c.requested("")
+	if text := c.run("today", "--offline"); !strings.Contains(text, "Readiness") {
+		t.Errorf("offline today lacks the cached readiness:\n%s", text)
+	}
+	if reqs := c.requested(""); len(reqs) != 0 {
+		t.Errorf("--offline sent requests with an expired token: %v", reqs)
+	}
+}
+
+func TestMockSyncResume(t *testing.T) {
+	c := newMockCLI(t, 50)
+	t.Setenv("XDG_DATA_HOME", "")
+	now := time.Now()
+	day := func(offset int) string { return now.AddDate(0, 0, offset).Format(dateLayout) }
+
+	type syncOutput struct {
+		Store       string       `json:"store"`
+		Collections []syncResult `json:"collections"`
+	}
+	result := func(out syncOutput, name string) syncResult {
+		t.Helper()
+		for _, r := range out.Collections {
+			if r.Collection == name {
+				if r.Error != "" {
+					t.Fatalf("%s: %s", name, r.Error)
+				}
+				return r
+			}
+		}
+		t.Fatalf("no %s in sync output", name)
+		return syncResult{}
+	}
+	state := func(store string) *collectionSyncState {
+		t.Helper()
+		return decodeJSON[syncState](t, mustRead(t, store+"/state.json")).Collections["daily_sleep"]
+	}
+
+	first := decodeJSON[syncOutput](t, c.run("sync", "--json"))
+	if r := result(first, "daily_sleep"); r.From != day(-29) || r.To != day(0) || r.Added != 30 {
+		t.Fatalf("first sync: %+v", r)
+	}
+	cs := state(first.Store)
+	if cs.Oldest != day(-29) || cs.Through != day(0) || cs.Watermark != day(-3) {
+		t.Fatalf("state after first sync: %+v", cs)
+	}
+
+	// The next run resumes after the watermark and adds nothing new.
+	c.requested("")
+	second := decodeJSON[syncOutput](t, c.run("sync", "--json"))
+	if r := result(second, "daily_sleep"); r.From != day(-2) || r.Added != 0 {
+		t.Errorf("resumed sync: %+v", r)
+	}
+	if reqs := c.requested("/daily_sleep?"); len(reqs) != 1 || !strings.Contains(reqs[0], "start_date="+day(-2)) {
+		t.Errorf("resumed sync requested %v, want one request from %s", reqs, day(-2))
+	}
+
+	// A --since after the stored range must not leave a gap: rewind the
+	// state as if the last sync was three weeks ago.
+	st := decodeJSON[syncState](t, mustRead(t, first.Store+"/state.json"))
+	st.Collections["daily_sleep"].Through = day(-20)
+	st.Collections["daily_sleep"].Watermark = day(-23)
+	data, _ := json.Marshal(st)
+	if err := os.WriteFile(first.Store+"/state.json", data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	third := decodeJSON[syncOutput](t, c.run("sync", "--since", day(-5), "--json"))
+	if r := result(third, "daily_sleep"); r.From != day(-22) {
+		t.Errorf("--since past the stored range: synced from %s, want %s", r.From, day(-22))
+	}
+	if cs := state(first.Store); cs.Oldest != day(-29) || cs.Through != day(0) {
+		t.Errorf("state after --since past the stored range: %+v", cs)
+	}
+
+	// An earlier --since backfills.
+	fourth := decodeJSON[syncOutput](t, c.run("sync", "--since", day(-35), "--json"))
+	if r := result(fourth, "daily_sleep"); r.From != day(-35) {
+		t.Errorf("backfill: synced from %s, want %s", r.From, day(-35))
+	}
+	if cs := state(first.Store); cs.Oldest != day(-35) {
+		t.Errorf("backfill: oldest = %s, want %s", cs.Oldest, day(-35))
+	}
+}
+
+func mustRead(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// offline is set by --offline: every GET is answered from cached responses,
+// however old, and nothing goes to the network.
+var offline bool
+
+var errOffline = errors.New("offline")
+
+// offlineRequested is the day range the user asked for. Metric commands pad
+// their queries by a day or more, and days outside this range aren't worth
+// a warning. Zero means each query is exactly what was asked for.
+var offlineRequested offlineInterval
+
+// setOfflineRequested records startDate..endDate as the requested range.
+func setOfflineRequested(startDate string, endDate string) {
+	if r, ok := offlineRange(url.Values{"start_date": {startDate}, "end_date": {endDate}}); ok {
+		offlineRequested = r
+	}
+}
+
+// offlineGet answers a GET from the cache. An exact match is used as is.
+// Otherwise a dated collection request is assembled from the local store and
+// every cached response for the same collection that overlaps the requested
+// range, and the requested days no cached response covered are reported on
+// stderr, so commands still print what they have.
+func offlineGet(fullURL string) ([]byte, error) {
+	if e, err := readCacheEntry(cachePath(fullURL)); err == nil && e.URL == fullURL {
+		return e.Body, nil
+	}
+
+	u, err := url.Parse(fullURL)
+	if err != nil {
+		return nil, err
+	}
+	name := cacheEndpointName(u.Path)
+	// Collection name in the local store, if the URL is in the current tree.
+	stored, inTree := strings.CutPrefix(fullURL, apiBase+"/")
+	stored, _, _ = strings.Cut(stored, "?")
+	if !inTree || strings.Contains(stored, "/") {
+		stored = ""
+	}
+	if u.Path == "/v2/usercollection/personal_info" {
+		stored = "personal_info"
+	}
+
+	want, dated := offlineRange(u.Query())
+	if !dated || u.Query().Get("next_token") != "" {
+		if body, ok := offlineStoredUndated(stored); ok && u.Query().Get("next_token") == "" {
+			return body, nil
+		}
+		return nil, fmt.Errorf("%w: %s is not cached (run without --offline to fetch it)", errOffline, name)
+	}
+
+	var covered []offlineInterval
+	seen := map[string]bool{}
+	var docs []json.RawMessage
+	addDoc := func(d json.RawMessage) {
+		at, ok := documentTime(d)
+		if !ok || !want.contains(at) {
+			return
+		}
+		key := documentKey(d)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		docs = append(docs, d)
+	}
+
+	// The local store from `oura sync` first, then any cached responses.
+	if r, ok := storedRange(stored); ok && r.overlaps(want) {
+		storedDocs, err := loadStoredDocuments(stored, want.Start, want.End)
+		if err == nil {
+			covered = append(covered, r)
+			for _, d := range storedDocs {
+				addDoc(d)
+			}
+		}
+	}
+	forEachCacheEntry(func(path string, info os.FileInfo, e cacheEntry) {
+		eu, err := url.Parse(e.URL)
+		if err != nil || eu.Scheme != u.Scheme || eu.Host != u.Host || eu.Path != u.Path {
+			return
+		}
+		q := eu.Query()
+		r, ok := offlineRange(q)
+		if !ok || !r.overlaps(want) {
+			return
+		}
+		// Continuation pages hold documents but the first page's entry
+		// already accounts for the range.
+		if q.Get("next_token") == "" {
+			covered = append(covered, r)
+		}
+
+		var resp MultiDocumentResponse[json.RawMessage]
+		if json.Unmarshal(e.Body, &resp) != nil {
+			return
+		}
+		for _, d := range resp.Data {
+			addDoc(d)
+		}
+	})
+
+	for _, gap := range want.minus(covered) {
+		if !offlineRequested.Start.IsZero() {
+			if gap = gap.intersect(offlineRequested); !gap.Start.Before(gap.End) {
+				continue
+			}
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Offline: %s not cached for %s\n", name, gap)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		a, _ := documentTime(docs[i])
+		b, _ := documentTime(docs[j])
+		return a.Before(b)
+	})
+	return json.Marshal(MultiDocumentResponse[json.RawMessage]{Data: append([]json.RawMessage{}, docs...)})
+}
+
+// offlineStoredUndated serves personal_info or ring_configuration from the
+// local store.
+func offlineStoredUndated(collection string) ([]byte, bool) {
+	if !undatedCollections[collection] {
+		return nil, false
+	}
+	docs, err := loadStoredDocuments(collection, time.Time{}, time.Time{})
+	if err != nil || len(docs) == 0 {
+		return nil, false
+	}
+	if collection == "personal_info" {
+		return docs[0], true
+	}
+	body, err := json.Marshal(MultiDocumentResponse[json.RawMessage]{Data: docs})
+	return body, err == nil
+}
+
+// offlineInterval is a half-open time range [Start, End). Date ranges cover
+// whole local days.
+type offlineInterval struct {
+	Start, End time.Time
+	days       bool
+}
+
+// offlineRange reads start_date/end_date or start_datetime/end_datetime.
+func offlineRange(q url.Values) (offlineInterval, bool) {
+	if s, e := q.Get("start_date"), q.Get("end_date"); s != "" && e != "" {
+		start, err1 := time.ParseInLocation(dateLayout, s, time.Local)
+		end, err2 := time.ParseInLocation(dateLayout, e, time.Local)
+		if err1 != nil || err2 != nil {
+			return offlineInterval{}, false
+		}
+		return offlineInterval{Start: start, End: end.AddDate(0, 0, 1), days: true}, true
+	}
+	if s, e := q.Get("start_datetime"), q.Get("end_datetime"); s != "" && e != "" {
+		start, err1 := time.Parse(time.RFC3339, s)
+		end, err2 := time.Parse(time.RFC3339, e)
+		if err1 != nil || err2 != nil {
+			return offlineInterval{}, false
+		}
+		return offlineInterval{Start: start, End: end}, true
+	}
+	return offlineInterval{}, false
+}
+
+func (r offlineInterval) overlaps(o offlineInterval) bool {
+	return r.Start.Before(o.End) && o.Start.Before(r.End)
+}
+
+func (r offlineInterval) contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// intersect returns the part of r inside o; it is empty (Start >= End) when
+// they don't overlap.
+func (r offlineInterval) intersect(o offlineInterval) offlineInterval {
+	if o.Start.After(r.Start) {
+		r.Start = o.Start
+	}
+	r.End = minTime(r.End, o.End)
+	return r
+}
+
+// minus returns the parts of r not covered by any of cover.
+func (r offlineInterval) minus(cover []offlineInterval) []offlineInterval {
+	sort.Slice(cover, func(i, j int) bool { return cover[i].Start.Before(cover[j].Start) })
+	var gaps []offlineInterval
+	at := r.Start
+	for _, c := range cover {
+		if !c.End.After(at) {
+			continue
+		}
+		if c.Start.After(at) {
+			gaps = append(gaps, offlineInterval{Start: at, End: minTime(c.Start, r.End), days: r.days})
+		}
+		at = c.End
+		if !at.Before(r.End) {
+			return gaps
+		}
+	}
+	if at.Before(r.End) {
+		gaps = append(gaps, offlineInterval{Start: at, End: r.End, days: r.days})
+	}
+	return gaps
+}
+
+func (r offlineInterval) String() string {
+	if r.days {
+		first, last := r.Start.Format(dateLayout), r.End.AddDate(0, 0, -1).Format(dateLayout)
+		if first == last {
+			return first
+		}
+		return first + ".." + last
+	}
+	return windowLabel(r.Start, r.End)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// documentTime places a document on the timeline: heart-rate samples by
+// timestamp, everything else by its day (start_day for enhanced tags and
+// rest mode periods).
+func documentTime(doc json.RawMessage) (time.Time, bool) {
+	var d struct {
+		Day       string `json:"day"`
+		StartDay  string `json:"start_day"`
+		Timestamp string `json:"timestamp"`
+		BPM       *int   `json:"bpm"`
+	}
+	if json.Unmarshal(doc, &d) != nil {
+		return time.Time{}, false
+	}
+	if d.BPM != nil {
+		t, err := time.Parse(time.RFC3339, d.Timestamp)
+		return t, err == nil
+	}
+	day := d.Day
+	if day == "" {
+		day = d.StartDay
+	}
+	t, err := time.ParseInLocation(dateLayout, day, time.Local)
+	return t, err == nil
+}
+
+// documentKey identifies a document across overlapping cached responses.
+func documentKey(doc json.RawMessage) string {
+	var d struct {
+		ID        string `json:"id"`
+		Timestamp string `json:"timestamp"`
+		Source    string `json:"source"`
+	}
+	json.Unmarshal(doc, &d)
+	if d.ID != "" {
+		return d.ID
+	}
+	if d.Timestamp != "" {
+		return d.Timestamp + "/" + d.Source
+	}
+	return strings.TrimSpace(string(doc))
+}
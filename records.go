@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Flattened records: the model structs turned into fixed columns, shared by
+// the tabular output formats and `oura export sqlite`. Nested structs
+// (contributors, sleep readiness, sample series) become one column per leaf
+// field, named by the JSON path; slices, maps and free-form values stay
+// whole as JSON text. Columns only change when a model does.
+
+// recordColumn is one leaf field of a model.
+type recordColumn struct {
+	// Path holds the JSON names from the top-level field down, e.g.
+	// ["contributors", "deep_sleep"].
+	Path []string
+	Kind reflect.Kind
+}
+
+func (c recordColumn) Name(sep string) string {
+	return strings.Join(c.Path, sep)
+}
+
+// recordColumns lists the columns of a model struct in field order.
+func recordColumns(t reflect.Type) []recordColumn {
+	var cols []recordColumn
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name := jsonFieldName(f)
+		if name == "" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			for _, c := range recordColumns(ft) {
+				cols = append(cols, recordColumn{Path: append([]string{name}, c.Path...), Kind: c.Kind})
+			}
+			continue
+		}
+		cols = append(cols, recordColumn{Path: []string{name}, Kind: ft.Kind()})
+	}
+	return cols
+}
+
+// recordValues returns v's values in recordColumns order: nil, int64,
+// float64 or string. A nil pointer (missing value or nested object) is nil.
+func recordValues(v reflect.Value) []any {
+	return appendRecordValues(nil, v)
+}
+
+func appendRecordValues(out []any, v reflect.Value) []any {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if jsonFieldName(f) == "" {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				n := 1
+				if f.Type.Elem().Kind() == reflect.Struct {
+					n = len(recordColumns(f.Type.Elem()))
+				}
+				out = append(out, make([]any, n)...)
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			out = appendRecordValues(out, fv)
+			continue
+		}
+		out = append(out, recordValue(fv))
+	}
+	return out
+}
+
+func recordValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return int64(1)
+		}
+		return int64(0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	}
+	// Slices, maps and free-form values are kept as JSON.
+	b, err := json.Marshal(v.Interface())
+	if err != nil || string(b) == "null" {
+		return nil
+	}
+	return string(b)
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name
+}
+
+// recordModel decodes an endpoint's response into its model struct.
+type recordModel struct {
+	Type reflect.Type
+	// decode returns the documents of a list response, or the one document
+	// of a single-document response.
+	decode func(body []byte, single bool) ([]reflect.Value, error)
+}
+
+// decodeDoc decodes one document into the model.
+func (m recordModel) decodeDoc(doc []byte) (reflect.Value, error) {
+	p := reflect.New(m.Type)
+	if err := json.Unmarshal(doc, p.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return p.Elem(), nil
+}
+
+// recordDay returns the record's "day" field, for models that have one.
+func recordDay(v reflect.Value) (string, bool) {
+	t := v.Type()
+	for i := range t.NumField() {
+		if jsonFieldName(t.Field(i)) == "day" && t.Field(i).Type.Kind() == reflect.String {
+			return v.Field(i).String(), true
+		}
+	}
+	return "", false
+}
+
+func modelFor[T any]() recordModel {
+	return recordModel{
+		Type: reflect.TypeFor[T](),
+		decode: func(body []byte, single bool) ([]reflect.Value, error) {
+			if single {
+				var doc T
+				if err := json.Unmarshal(body, &doc); err != nil {
+					return nil, fmt.Errorf("failed to parse response: %w", err)
+				}
+				return []reflect.Value{reflect.ValueOf(doc)}, nil
+			}
+			var resp MultiDocumentResponse[T]
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return nil, fmt.Errorf("failed to parse response: %w", err)
+			}
+			docs := make([]reflect.Value, len(resp.Data))
+			for i := range resp.Data {
+				docs[i] = reflect.ValueOf(resp.Data[i])
+			}
+			return docs, nil
+		},
+	}
+}
+
+// endpointModels maps each endpoint the metric commands fetch to its model.
+var endpointModels = map[string]recordModel{
+	"/sleep":                    modelFor[SleepRecord](),
+	"/daily_sleep":              modelFor[DailySleepRecord](),
+	"/daily_readiness":          modelFor[ReadinessRecord](),
+	"/daily_activity":           modelFor[ActivityRecord](),
+	"/heartrate":                modelFor[HeartRateRecord](),
+	"/daily_stress":             modelFor[StressRecord](),
+	"/daily_spo2":               modelFor[SpO2Record](),
+	"/daily_resilience":         modelFor[ResilienceRecord](),
+	"/vO2_max":                  modelFor[VO2MaxRecord](),
+	"/workout":                  modelFor[WorkoutRecord](),
+	"/daily_cardiovascular_age": modelFor[CardiovascularAgeRecord](),
+	"/sleep_time":               modelFor[SleepTimeRecord](),
+	"/tag":                      modelFor[TagModel](),
+	"/enhanced_tag":             modelFor[EnhancedTagModel](),
+	"/session":                  modelFor[SessionModel](),
+	"/rest_mode_period":         modelFor[RestModePeriodModel](),
+	"/ring_configuration":       modelFor[RingConfigurationModel](),
+	"/personal_info":            modelFor[PersonalInfoResponse](),
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type SleepTimeResponse struct {
+	Data []SleepTimeRecord `json:"data"`
+}
+
+type SleepTimeRecord struct {
+	ID             string           `json:"id"`
+	Day            string           `json:"day"`
+	OptimalBedtime *SleepTimeWindow `json:"optimal_bedtime"`
+	Recommendation string           `json:"recommendation"`
+	Status         string           `json:"status"`
+}
+
+// SleepTimeWindow offsets are seconds from midnight of the day, in the
+// timezone given by DayTZ (seconds east of GMT). Negative offsets fall on
+// the previous evening.
+type SleepTimeWindow struct {
+	DayTZ       int `json:"day_tz"`
+	StartOffset int `json:"start_offset"`
+	EndOffset   int `json:"end_offset"`
+}
+
+// Clock converts the window into wall-clock start/end times in the day's
+// own timezone.
+func (w SleepTimeWindow) Clock(day string) (start time.Time, end time.Time, err error) {
+	loc := time.FixedZone("", w.DayTZ)
+	midnight, err := time.ParseInLocation(dateLayout, day, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start = midnight.Add(time.Duration(w.StartOffset) * time.Second)
+	end = midnight.Add(time.Duration(w.EndOffset) * time.Second)
+	return start, end, nil
+}
+
+// Values from SleepTimeRecommendation in refs/openapi-1.27.json.
+var sleepTimeRecommendations = map[string]string{
+	"improve_efficiency":     "Improve sleep efficiency",
+	"earlier_bedtime":        "Go to bed earlier",
+	"later_bedtime":          "Go to bed later",
+	"earlier_wake_up_time":   "Wake up earlier",
+	"later_wake_up_time":     "Wake up later",
+	"follow_optimal_bedtime": "Follow your optimal bedtime",
+}
+
+// Values from SleepTimeStatus in refs/openapi-1.27.json.
+var sleepTimeStatuses = map[string]string{
+	"not_enough_nights":        "Not enough nights yet",
+	"not_enough_recent_nights": "Not enough recent nights",
+	"bad_sleep_quality":        "Sleep quality too low to recommend",
+	"only_recommended_found":   "Recommended bedtime found",
+	"optimal_found":            "Optimal bedtime found",
+}
+
+func fetchSleepTime(startDate string, endDate string) {
+	body, err := apiGetAll("/sleep_time", paddedRangeParams(startDate, endDate, 0, 0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var data SleepTimeResponse
+	json.Unmarshal(body, &data)
+
+	for i, date := range daysInRange(startDate, endDate) {
+		if i > 0 {
+			fmt.Println()
+		}
+		records := recordsForDay(data.Data, date, func(r SleepTimeRecord) string { return r.Day })
+		if len(records) == 0 {
+			fmt.Println("No sleep time data for", date)
+			continue
+		}
+		printSleepTime(records[0])
+	}
+}
+
+func printSleepTime(r SleepTimeRecord) {
+	fmt.Printf("🛌 Sleep Time - %s\n", r.Day)
+	fmt.Println(strings.Repeat("─", 40))
+	if r.OptimalBedtime != nil {
+		start, end, err := r.OptimalBedtime.Clock(r.Day)
+		if err == nil {
+			fmt.Printf("Optimal Bedtime: %s → %s\n", start.Format("3:04 PM"), end.Format("3:04 PM"))
+		}
+	} else {
+		fmt.Println("Optimal Bedtime: n/a")
+	}
+	if r.Recommendation != "" {
+		fmt.Printf("Recommendation:  %s\n", firstNonEmpty(sleepTimeRecommendations[r.Recommendation], r.Recommendation))
+	}
+	if r.Status != "" {
+		fmt.Printf("Status:          %s\n", firstNonEmpty(sleepTimeStatuses[r.Status], r.Status))
+	}
+}
+
+func fetchSleepTimeJSON(startDate string, endDate string) {
+	fetchEndpointsJSON("sleep-time", startDate, endDate, startDate, endDate, []string{"/sleep_time"})
+}
@@ -0,0 +1,429 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// The schema `oura export sqlite` writes. Each collection is a table whose
+// columns are the model's flattened fields (see records.go) joined with "_",
+// keyed by the document id. Nested contributors, sample series and lists go
+// to child tables whose first column references the parent document, so
+// they are queried with plain joins instead of json_each.
+
+// exportChild is a child table holding some of a model's nested fields.
+type exportChild struct {
+	Table string
+	// Parent is the column referencing the parent document's id.
+	Parent string
+	// Fields are the JSON names of the model fields stored in the table:
+	// one contributors struct, sample series (SampleModel, or its raw JSON),
+	// or one list of strings.
+	Fields []string
+}
+
+// exportRef adds a column referencing a document in an earlier table that
+// the API only relates implicitly (by day, or by time).
+type exportRef struct {
+	Column string
+	Table  string
+	// index builds a lookup from the referenced table's documents; it
+	// returns the referenced id for a document, or nil.
+	index func(targets []reflect.Value) func(doc reflect.Value) any
+}
+
+// exportData is one table's documents, decoded into its model.
+type exportData struct {
+	exportTable
+	Docs []reflect.Value
+}
+
+type sqliteColumn struct {
+	Name string
+	Type string // INTEGER, REAL or TEXT
+	// field is the JSON name of the top-level model field it comes from.
+	field string
+}
+
+func sqliteType(k reflect.Kind) string {
+	switch k {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	}
+	return "TEXT"
+}
+
+func sqliteQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// columns lists the parent table's columns: the model's flattened fields
+// except those moved to child tables.
+func (t exportTable) columns() []sqliteColumn {
+	var cols []sqliteColumn
+	for _, c := range recordColumns(endpointModels[t.Endpoint].Type) {
+		cols = append(cols, sqliteColumn{Name: c.Name("_"), Type: sqliteType(c.Kind), field: c.Path[0]})
+	}
+	return cols
+}
+
+func (t exportTable) childField(field string) bool {
+	for _, c := range t.Children {
+		if slices.Contains(c.Fields, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// key is the primary key: the document id, or Key for models without one.
+func (t exportTable) key() []string {
+	if len(t.Key) > 0 {
+		return t.Key
+	}
+	return []string{"id"}
+}
+
+// schema returns the CREATE statements for the table, its child tables and
+// their indexes.
+func (t exportTable) schema() ([]string, error) {
+	var defs []string
+	var names []string
+	for _, c := range t.columns() {
+		if t.childField(c.field) {
+			continue
+		}
+		names = append(names, c.Name)
+		def := sqliteQuote(c.Name) + " " + c.Type
+		if slices.Contains(t.key(), c.Name) {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+	}
+	for _, k := range t.key() {
+		if !slices.Contains(names, k) {
+			return nil, fmt.Errorf("%s: key column %q is not in the model", t.Name, k)
+		}
+	}
+	if t.Ref != nil {
+		defs = append(defs, fmt.Sprintf("%s TEXT REFERENCES %s(id)", sqliteQuote(t.Ref.Column), sqliteQuote(t.Ref.Table)))
+	}
+	defs = append(defs, "PRIMARY KEY ("+quoteList(t.key())+")")
+	stmts := []string{fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", sqliteQuote(t.Name), strings.Join(defs, ",\n  "))}
+
+	if slices.Contains(names, "day") {
+		stmts = append(stmts, fmt.Sprintf("CREATE INDEX %s ON %s(day)", sqliteQuote(t.Name+"_day"), sqliteQuote(t.Name)))
+	}
+	if t.Ref != nil {
+		stmts = append(stmts, fmt.Sprintf("CREATE INDEX %s ON %s(%s)",
+			sqliteQuote(t.Name+"_"+t.Ref.Column), sqliteQuote(t.Name), sqliteQuote(t.Ref.Column)))
+	}
+
+	model := endpointModels[t.Endpoint].Type
+	for _, c := range t.Children {
+		kind, valueType, err := childKind(model, c.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name, c.Table, err)
+		}
+		parent := fmt.Sprintf("%s TEXT NOT NULL REFERENCES %s(id) ON DELETE CASCADE", sqliteQuote(c.Parent), sqliteQuote(t.Name))
+		var cols, key string
+		switch kind {
+		case childContributors:
+			cols, key = "contributor TEXT NOT NULL,\n  value "+valueType, "contributor"
+		case childSamples:
+			cols, key = "series TEXT NOT NULL,\n  timestamp TEXT NOT NULL,\n  value REAL", "series, timestamp"
+		case childList:
+			cols, key = "position INTEGER NOT NULL,\n  value TEXT", "position"
+		}
+		stmts = append(stmts, fmt.Sprintf("CREATE TABLE %s (\n  %s,\n  %s,\n  PRIMARY KEY (%s, %s)\n)",
+			sqliteQuote(c.Table), parent, cols, sqliteQuote(c.Parent), key))
+	}
+	return stmts, nil
+}
+
+func quoteList(names []string) string {
+	q := make([]string, len(names))
+	for i, n := range names {
+		q[i] = sqliteQuote(n)
+	}
+	return strings.Join(q, ", ")
+}
+
+type childTableKind int
+
+const (
+	childContributors childTableKind = iota // one row per struct field
+	childSamples                            // one row per sample of each series
+	childList                               // one row per list element
+)
+
+var (
+	sampleModelType = reflect.TypeFor[SampleModel]()
+	rawJSONType     = reflect.TypeFor[json.RawMessage]()
+)
+
+// childKind works out how fields are stored from their types, and the
+// value column's type for contributors.
+func childKind(model reflect.Type, fields []string) (childTableKind, string, error) {
+	var kinds []childTableKind
+	valueType := "INTEGER"
+	for _, name := range fields {
+		f, ok := jsonField(model, name)
+		if !ok {
+			return 0, "", fmt.Errorf("no field %q in %s", name, model.Name())
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft == sampleModelType || ft == rawJSONType:
+			kinds = append(kinds, childSamples)
+		case ft.Kind() == reflect.Struct:
+			kinds = append(kinds, childContributors)
+			for _, c := range recordColumns(ft) {
+				if sqliteType(c.Kind) != "INTEGER" {
+					valueType = "REAL"
+				}
+			}
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.String:
+			kinds = append(kinds, childList)
+		default:
+			return 0, "", fmt.Errorf("field %q has unsupported type %s", name, f.Type)
+		}
+	}
+	if len(kinds) == 0 || slices.ContainsFunc(kinds, func(k childTableKind) bool { return k != kinds[0] }) {
+		return 0, "", fmt.Errorf("fields %v don't share one layout", fields)
+	}
+	if kinds[0] != childSamples && len(fields) > 1 {
+		return 0, "", fmt.Errorf("only sample series can share a table")
+	}
+	return kinds[0], valueType, nil
+}
+
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		if jsonFieldName(t.Field(i)) == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// childRows returns the rows of one child table for a document, after the
+// parent id column.
+func childRows(doc reflect.Value, fields []string) ([][]any, error) {
+	var rows [][]any
+	for _, name := range fields {
+		f, _ := jsonField(doc.Type(), name)
+		v := doc.FieldByIndex(f.Index)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		switch {
+		case v.Type() == rawJSONType:
+			raw := v.Interface().(json.RawMessage)
+			if len(raw) == 0 || string(raw) == "null" {
+				continue
+			}
+			var s SampleModel
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			r, err := sampleRows(name, s)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, r...)
+		case v.Type() == sampleModelType:
+			r, err := sampleRows(name, v.Interface().(SampleModel))
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, r...)
+		case v.Kind() == reflect.Struct:
+			vals := recordValues(v)
+			for i, c := range recordColumns(v.Type()) {
+				rows = append(rows, []any{c.Name("_"), vals[i]})
+			}
+		case v.Kind() == reflect.Slice:
+			for i := range v.Len() {
+				rows = append(rows, []any{int64(i), v.Index(i).String()})
+			}
+		}
+	}
+	return rows, nil
+}
+
+// sampleRows expands a sample series into (series, timestamp, value) rows,
+// skipping missing samples.
+func sampleRows(series string, s SampleModel) ([][]any, error) {
+	if len(s.Items) == 0 {
+		return nil, nil
+	}
+	start, err := time.Parse(time.RFC3339, s.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid timestamp %q", series, s.Timestamp)
+	}
+	step := time.Duration(s.Interval * float64(time.Second))
+	var rows [][]any
+	for i, v := range s.Items {
+		if v == nil {
+			continue
+		}
+		ts := start.Add(time.Duration(i) * step).Format(time.RFC3339)
+		rows = append(rows, []any{series, ts, *v})
+	}
+	return rows, nil
+}
+
+// writeSQLite creates a new database at path holding the tables, in one
+// transaction with foreign keys enforced.
+func writeSQLite(path string, tables []exportData) (err error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	// One connection, so the pragma applies to the transaction below.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	docsByTable := map[string][]reflect.Value{}
+	for _, t := range tables {
+		stmts, err := t.schema()
+		if err != nil {
+			return err
+		}
+		for _, s := range stmts {
+			if _, err := tx.Exec(s); err != nil {
+				return fmt.Errorf("%s: %w", t.Name, err)
+			}
+		}
+		if err := insertDocuments(tx, t, docsByTable); err != nil {
+			return fmt.Errorf("%s: %w", t.Name, err)
+		}
+		docsByTable[t.Name] = t.Docs
+	}
+	return tx.Commit()
+}
+
+// insertDocuments writes a table's documents and their child rows. A
+// document repeated across requests (same key) is written once, last copy
+// wins.
+func insertDocuments(tx *sql.Tx, t exportData, docsByTable map[string][]reflect.Value) error {
+	var cols []string
+	var keep []int
+	for i, c := range t.columns() {
+		if !t.childField(c.field) {
+			cols = append(cols, c.Name)
+			keep = append(keep, i)
+		}
+	}
+	var ref func(reflect.Value) any
+	if t.Ref != nil {
+		cols = append(cols, t.Ref.Column)
+		ref = t.Ref.index(docsByTable[t.Ref.Table])
+	}
+
+	keyIdx := make([]int, len(t.key()))
+	for i, k := range t.key() {
+		keyIdx[i] = slices.Index(cols, k)
+	}
+	var rows [][]any
+	var docs []reflect.Value
+	seen := map[string]int{}
+	for _, d := range t.Docs {
+		all := recordValues(d)
+		row := make([]any, 0, len(cols))
+		for _, i := range keep {
+			row = append(row, all[i])
+		}
+		if ref != nil {
+			row = append(row, ref(d))
+		}
+		var key []string
+		for _, i := range keyIdx {
+			key = append(key, fmt.Sprint(row[i]))
+		}
+		k := strings.Join(key, "\x00")
+		if i, ok := seen[k]; ok {
+			rows[i], docs[i] = row, d
+			continue
+		}
+		seen[k] = len(rows)
+		rows = append(rows, row)
+		docs = append(docs, d)
+	}
+
+	ins, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		sqliteQuote(t.Name), quoteList(cols), placeholders(len(cols))))
+	if err != nil {
+		return err
+	}
+	defer ins.Close()
+	for _, row := range rows {
+		if _, err := ins.Exec(row...); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range t.Children {
+		kind, _, err := childKind(endpointModels[t.Endpoint].Type, c.Fields)
+		if err != nil {
+			return err
+		}
+		n := 3
+		if kind == childSamples {
+			n = 4
+		}
+		stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", sqliteQuote(c.Table), placeholders(n)))
+		if err != nil {
+			return err
+		}
+		for i, d := range docs {
+			crows, err := childRows(d, c.Fields)
+			if err != nil {
+				stmt.Close()
+				return fmt.Errorf("%s %v: %w", c.Table, rows[i][keyIdx[0]], err)
+			}
+			for _, cr := range crows {
+				if _, err := stmt.Exec(append([]any{rows[i][keyIdx[0]]}, cr...)...); err != nil {
+					stmt.Close()
+					return fmt.Errorf("%s: %w", c.Table, err)
+				}
+			}
+		}
+		stmt.Close()
+	}
+	return nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The local store written by `oura sync`: one directory per collection with
+// a JSON array of documents per month (<store>/<collection>/YYYY-MM.json),
+// deduplicated by documentKey. Undated collections are a single
+// <collection>.json file. state.json records how far each collection has
+// been synced.
+
+// undatedCollections are stored whole rather than by month.
+var undatedCollections = map[string]bool{
+	"personal_info":      true,
+	"ring_configuration": true,
+}
+
+// getStoreDir returns the store for the current API target, so sandbox or
+// mock-server data never mixes with real data.
+func getStoreDir() string {
+	base := config.StoreDir
+	if base == "" {
+		base = os.Getenv("XDG_DATA_HOME")
+		if base == "" {
+			home, _ := os.UserHomeDir()
+			base = filepath.Join(home, ".local", "share")
+		}
+		base = filepath.Join(base, "oura")
+	}
+	if apiHost != defaultAPIHost {
+		if u, err := url.Parse(apiHost); err == nil {
+			base = filepath.Join(base, "hosts", strings.NewReplacer(":", "_", ".", "_").Replace(u.Host))
+		}
+	}
+	if sandbox {
+		base = filepath.Join(base, "sandbox")
+	}
+	return base
+}
+
+// collectionSyncState tracks one collection. Oldest..Through is the range
+// the store holds; Watermark is the last day treated as final, where the
+// next sync resumes.
+type collectionSyncState struct {
+	Oldest    string    `json:"oldest,omitempty"`
+	Through   string    `json:"through,omitempty"`
+	Watermark string    `json:"watermark,omitempty"`
+	LastSync  time.Time `json:"last_sync"`
+}
+
+type syncState struct {
+	Collections map[string]*collectionSyncState `json:"collections"`
+}
+
+func loadSyncState() (*syncState, error) {
+	st := &syncState{Collections: map[string]*collectionSyncState{}}
+	data, err := os.ReadFile(filepath.Join(getStoreDir(), "state.json"))
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("corrupt sync state: %w", err)
+	}
+	if st.Collections == nil {
+		st.Collections = map[string]*collectionSyncState{}
+	}
+	return st, nil
+}
+
+func saveSyncState(st *syncState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(getStoreDir(), "state.json"), data)
+}
+
+// writeFileAtomic replaces path so an interrupted write never leaves a
+// truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// storeDocuments merges docs into the collection, replacing documents with
+// the same key. It returns how many were new.
+func storeDocuments(collection string, docs []json.RawMessage) (added int, err error) {
+	if undatedCollections[collection] {
+		return storeUndated(collection, docs)
+	}
+
+	byMonth := map[string][]json.RawMessage{}
+	for _, d := range docs {
+		t, ok := documentTime(d)
+		if !ok {
+			continue
+		}
+		m := t.Local().Format("2006-01")
+		byMonth[m] = append(byMonth[m], d)
+	}
+
+	for month, incoming := range byMonth {
+		path := filepath.Join(getStoreDir(), collection, month+".json")
+		existing, err := readDocuments(path)
+		if err != nil {
+			return added, err
+		}
+		merged, n := mergeDocuments(existing, incoming)
+		added += n
+		sort.SliceStable(merged, func(i, j int) bool {
+			a, _ := documentTime(merged[i])
+			b, _ := documentTime(merged[j])
+			return a.Before(b)
+		})
+		if err := writeDocuments(path, merged); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+func storeUndated(collection string, docs []json.RawMessage) (int, error) {
+	path := filepath.Join(getStoreDir(), collection+".json")
+	existing, err := readDocuments(path)
+	if err != nil {
+		return 0, err
+	}
+	merged, added := mergeDocuments(existing, docs)
+	return added, writeDocuments(path, merged)
+}
+
+func mergeDocuments(existing, incoming []json.RawMessage) ([]json.RawMessage, int) {
+	index := make(map[string]int, len(existing))
+	for i, d := range existing {
+		index[documentKey(d)] = i
+	}
+	added := 0
+	for _, d := range incoming {
+		k := documentKey(d)
+		if i, ok := index[k]; ok {
+			existing[i] = d
+			continue
+		}
+		index[k] = len(existing)
+		existing = append(existing, d)
+		added++
+	}
+	return existing, added
+}
+
+func readDocuments(path string) ([]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var docs []json.RawMessage
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return docs, nil
+}
+
+func writeDocuments(path string, docs []json.RawMessage) error {
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// loadStoredDocuments returns the collection's documents whose day (or
+// timestamp) falls in [start, end).
+func loadStoredDocuments(collection string, start, end time.Time) ([]json.RawMessage, error) {
+	if undatedCollections[collection] {
+		return readDocuments(filepath.Join(getStoreDir(), collection+".json"))
+	}
+
+	var out []json.RawMessage
+	s := start.Local()
+	first := time.Date(s.Year(), s.Month(), 1, 0, 0, 0, 0, time.Local)
+	for m := first; m.Before(end); m = m.AddDate(0, 1, 0) {
+		docs, err := readDocuments(filepath.Join(getStoreDir(), collection, m.Format("2006-01")+".json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range docs {
+			if t, ok := documentTime(d); ok && !t.Before(start) && t.Before(end) {
+				out = append(out, d)
+			}
+		}
+	}
+	return out, nil
+}
+
+// storedRange is the span of days the store holds for a dated collection.
+func storedRange(collection string) (offlineInterval, bool) {
+	st, err := loadSyncState()
+	if err != nil {
+		return offlineInterval{}, false
+	}
+	cs := st.Collections[collection]
+	if cs == nil || cs.Oldest == "" || cs.Through == "" {
+		return offlineInterval{}, false
+	}
+	start, end := dayWindow(cs.Oldest, cs.Through)
+	return offlineInterval{Start: start, End: end, days: true}, true
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSyncDays is how far the first sync of a collection reaches back.
+	defaultSyncDays = 30
+	// Days per request; state is saved after each so an interrupted sync
+	// resumes where it stopped.
+	syncChunkDays          = 30
+	syncHeartRateChunkDays = 7
+)
+
+type syncResult struct {
+	Collection string `json:"collection"`
+	From       string `json:"from,omitempty"`
+	To         string `json:"to,omitempty"`
+	Fetched    int    `json:"fetched"`
+	Added      int    `json:"added"`
+	Error      string `json:"error,omitempty"`
+}
+
+func handleSync(args []string, opts Options) {
+	if opts.Help {
+		printSyncUsage()
+		return
+	}
+	if len(args) > 0 && args[0] == "status" {
+		syncStatus(opts)
+		return
+	}
+	if offline {
+		exitErr(fmt.Errorf("sync needs the network; drop --offline"))
+	}
+
+	flags, pos, err := parseLongFlags(args)
+	if err != nil {
+		exitErr(err)
+	}
+	if len(pos) != 0 {
+		printSyncUsage()
+		os.Exit(1)
+	}
+	now := time.Now()
+	var since string
+	if v := firstFlag(flags, "since"); v != "" {
+		since, _, err = resolveDateExpr(v, now)
+		if err != nil {
+			exitErr(err)
+		}
+	}
+
+	// The store is the durable copy: sync always reads complete ranges
+	// from the API rather than from capped pages or the response cache.
+	maxPages = 0
+	cacheMode.Disabled = true
+
+	st, err := loadSyncState()
+	if err != nil {
+		exitErr(err)
+	}
+
+	today := now.Format(dateLayout)
+	final := now.AddDate(0, 0, -cacheGraceDays()).Format(dateLayout)
+
+	var results []syncResult
+	for _, name := range syncCollections() {
+		cs := st.Collections[name]
+		if cs == nil {
+			cs = &collectionSyncState{}
+			st.Collections[name] = cs
+		}
+		var r syncResult
+		if undatedCollections[name] {
+			r = syncUndated(name, cs, now)
+		} else {
+			r = syncDated(st, name, cs, syncStart(cs, since, now), today, final, now)
+		}
+		results = append(results, r)
+		if err := saveSyncState(st); err != nil {
+			exitErr(err)
+		}
+	}
+
+	failed := false
+	for _, r := range results {
+		failed = failed || r.Error != ""
+	}
+	if opts.JSON {
+		writeJSONToStdout(map[string]any{"store": getStoreDir(), "collections": results})
+	} else {
+		printSyncResults(results)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// syncCollections lists every collection the store keeps.
+func syncCollections() []string {
+	var names []string
+	for _, c := range collections {
+		names = append(names, c.Path)
+	}
+	return append(names, "heartrate", "personal_info")
+}
+
+// syncStart resumes the day after the watermark, or defaultSyncDays back on
+// the first sync. --since only reaches further back: the stored range must
+// stay contiguous, so a --since after the resume point is ignored rather
+// than leaving a gap behind the documents already stored.
+func syncStart(cs *collectionSyncState, since string, now time.Time) string {
+	start := now.AddDate(0, 0, -(defaultSyncDays - 1)).Format(dateLayout)
+	if cs.Watermark != "" {
+		start = nextDay(cs.Watermark)
+	}
+	if since != "" && (cs.Through == "" || since < start) {
+		return since
+	}
+	return start
+}
+
+func syncDated(st *syncState, name string, cs *collectionSyncState, start, today, final string, now time.Time) syncResult {
+	r := syncResult{Collection: name, From: start, To: today}
+	if start > today {
+		r.From = ""
+		r.To = ""
+		return r
+	}
+
+	chunk := syncChunkDays
+	if name == "heartrate" {
+		chunk = syncHeartRateChunkDays
+	}
+
+	for from := start; from <= today; {
+		f, _ := time.ParseInLocation(dateLayout, from, time.Local)
+		to := f.AddDate(0, 0, chunk-1).Format(dateLayout)
+		if to > today {
+			to = today
+		}
+
+		params := url.Values{}
+		if name == "heartrate" {
+			params = heartRateParams(dayWindow(from, to))
+		} else {
+			params.Set("start_date", from)
+			params.Set("end_date", to)
+		}
+		body, err := apiGetAll("/"+name, params)
+		if err != nil {
+			r.Error = err.Error()
+			return r
+		}
+		var resp MultiDocumentResponse[json.RawMessage]
+		if err := json.Unmarshal(body, &resp); err != nil {
+			r.Error = fmt.Sprintf("failed to parse response: %v", err)
+			return r
+		}
+		added, err := storeDocuments(name, resp.Data)
+		r.Fetched += len(resp.Data)
+		r.Added += added
+		if err != nil {
+			r.Error = err.Error()
+			return r
+		}
+
+		if cs.Oldest == "" || from < cs.Oldest {
+			cs.Oldest = from
+		}
+		if to > cs.Through {
+			cs.Through = to
+		}
+		if w := min(to, final); w > cs.Watermark {
+			cs.Watermark = w
+		}
+		cs.LastSync = now
+		if err := saveSyncState(st); err != nil {
+			r.Error = err.Error()
+			return r
+		}
+		from = nextDay(to)
+	}
+	return r
+}
+
+func syncUndated(name string, cs *collectionSyncState, now time.Time) syncResult {
+	r := syncResult{Collection: name}
+	var docs []json.RawMessage
+	if name == "personal_info" {
+		body, err := apiGet("/personal_info", nil)
+		if err != nil {
+			r.Error = err.Error()
+			return r
+		}
+		docs = []json.RawMessage{body}
+	} else {
+		body, err := apiGetAll("/"+name, nil)
+		if err != nil {
+			r.Error = err.Error()
+			return r
+		}
+		var resp MultiDocumentResponse[json.RawMessage]
+		if err := json.Unmarshal(body, &resp); err != nil {
+			r.Error = fmt.Sprintf("failed to parse response: %v", err)
+			return r
+		}
+		docs = resp.Data
+	}
+
+	added, err := storeDocuments(name, docs)
+	r.Fetched, r.Added = len(docs), added
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	cs.LastSync = now
+	return r
+}
+
+func nextDay(day string) string {
+	t, _ := time.ParseInLocation(dateLayout, day, time.Local)
+	return t.AddDate(0, 0, 1).Format(dateLayout)
+}
+
+func printSyncResults(results []syncResult) {
+	fmt.Printf("Synced to %s\n", getStoreDir())
+	fmt.Println(strings.Repeat("-", 72))
+	for _, r := range results {
+		span := "-"
+		if r.From != "" {
+			span = r.From + ".." + r.To
+		}
+		line := fmt.Sprintf("%-26s %-22s %6d fetched %6d new", r.Collection, span, r.Fetched, r.Added)
+		if r.Error != "" {
+			line += "  ERROR: " + truncate(r.Error, 60)
+		}
+		fmt.Println(line)
+	}
+}
+
+func syncStatus(opts Options) {
+	st, err := loadSyncState()
+	if err != nil {
+		exitErr(err)
+	}
+	if opts.JSON {
+		writeJSONToStdout(map[string]any{"store": getStoreDir(), "collections": st.Collections})
+		return
+	}
+
+	fmt.Printf("Local store: %s\n", getStoreDir())
+	fmt.Println(strings.Repeat("-", 72))
+	if len(st.Collections) == 0 {
+		fmt.Println("Nothing synced yet (run: oura sync)")
+		return
+	}
+	for _, name := range syncCollections() {
+		cs := st.Collections[name]
+		if cs == nil || cs.LastSync.IsZero() {
+			fmt.Printf("%-26s never synced\n", name)
+			continue
+		}
+		span := "-"
+		if cs.Oldest != "" {
+			span = cs.Oldest + ".." + cs.Through
+		}
+		fmt.Printf("%-26s %-22s final through %-10s  synced %s\n",
+			name, span, firstNonEmpty(cs.Watermark, "-"), cs.LastSync.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+func printSyncUsage() {
+	fmt.Println(`Usage: oura sync [--since <date>] [--json|-j]
+       oura sync status [--json|-j]
+
+Pull every usercollection type plus heartrate into a local store, keeping
+one copy of each document (by id). Each run resumes after the last day
+treated as final (older than cache_grace_days, default 3), so recent days
+are refetched until they settle. Progress is saved after every request, so
+an interrupted sync picks up where it stopped.
+
+Flags:
+  --since <date>   Backfill from this date (any date expression); a date
+                   after the last final day resumes as usual
+
+The first sync reaches back 30 days. The store lives in
+$XDG_DATA_HOME/oura (default ~/.local/share/oura), or "store_dir" in
+config.json; --offline also reads from it.`)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// outputTemplate is set from --template / --template-file. Metric and
+// list commands render it once per record, against the record's model
+// struct (SleepRecord, ReadinessRecord, …); `today` and `all` render it
+// once against a DaySummary.
+var outputTemplate *template.Template
+
+// templateCollection is the collection of the record being rendered, for
+// the collection func.
+var templateCollection string
+
+func templateOutput() bool {
+	return outputTemplate != nil
+}
+
+var templateFuncs = template.FuncMap{
+	// collection names the record's collection, e.g. "daily_sleep", to
+	// tell records apart in commands that combine collections.
+	"collection": func() string { return templateCollection },
+	// duration formats seconds as "7h 12m".
+	"duration": func(v any) string {
+		n, ok := templateNumber(v)
+		if !ok {
+			return "n/a"
+		}
+		return formatDuration(int(n))
+	},
+	// localtime formats an RFC 3339 timestamp in the local timezone, as
+	// "3:04 PM" or with the given Go layout.
+	"localtime": func(s string, layout ...string) (string, error) {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return "", err
+		}
+		l := "3:04 PM"
+		if len(layout) > 0 {
+			l = layout[0]
+		}
+		return t.Local().Format(l), nil
+	},
+	// pct formats a percentage: "pct 85" is "85%", "pct .A .B" is A/B.
+	"pct": func(v any, total ...any) string {
+		n, ok := templateNumber(v)
+		if !ok {
+			return "n/a"
+		}
+		if len(total) > 0 {
+			d, ok := templateNumber(total[0])
+			if !ok || d == 0 {
+				return "n/a"
+			}
+			n = n / d * 100
+		}
+		return fmt.Sprintf("%.0f%%", math.Round(n))
+	},
+}
+
+// templateNumber reads any integer or float, through pointers; nil is not
+// a number.
+func templateNumber(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return 0, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// parseOutputTemplate compiles --template text.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	t, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return t, nil
+}
+
+// renderTemplate renders the template for one record and ends the output
+// with a newline.
+func renderTemplate(collection string, data any) {
+	if v := reflect.ValueOf(data); filteredOutput() && v.Kind() == reflect.Struct && !outputFilter.matchValue(v) {
+		return
+	}
+	executeTemplate(collection, data)
+}
+
+func executeTemplate(collection string, data any) {
+	templateCollection = collection
+	var buf bytes.Buffer
+	if err := outputTemplate.Execute(&buf, data); err != nil {
+		exitErr(err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	os.Stdout.Write(buf.Bytes())
+}
+
+// renderRecords renders the template for each document.
+func renderRecords[T any](collection string, docs []T) {
+	for _, d := range docs {
+		renderTemplate(collection, d)
+	}
+}
+
+// renderEndpoints is the template form of fetchEndpointsJSON: every record
+// whose day (if any) is in startDate..endDate, in endpoint order.
+func renderEndpoints(startDate, endDate string, reqs []endpointRequest, results []endpointResponse) {
+	failed := false
+	for i, r := range results {
+		name := strings.TrimPrefix(reqs[i].Endpoint, "/")
+		var docs []reflect.Value
+		err := r.Err
+		if err == nil {
+			docs, err = endpointModels[reqs[i].Endpoint].decode(r.Body, reqs[i].Single)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", name, err)
+			failed = true
+			continue
+		}
+		for _, d := range docs {
+			if day, ok := recordDay(d); ok && (day < startDate || day > endDate) {
+				continue
+			}
+			renderTemplate(name, d.Interface())
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// renderDaySummary renders the template once for date. --where filters
+// the day's records, as in the other outputs of `today` and `all`, and
+// nothing is rendered when none match.
+func renderDaySummary(date string) {
+	var types []reflect.Type
+	for _, ep := range daySummaryEndpoints {
+		types = append(types, endpointModels[ep].Type)
+	}
+	checkFilter(types...)
+	s, failed := fetchDaySummary(date)
+	if !filteredOutput() || outputFilter.filterDaySummary(&s) {
+		executeTemplate("", s)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// loadTemplateFlag returns --template text, or the contents of
+// --template-file.
+func loadTemplateFlag(inline, file string) (string, error) {
+	if file == "" {
+		return inline, nil
+	}
+	if inline != "" {
+		return "", fmt.Errorf("use either --template or --template-file, not both")
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"time"
 )
 
 type MultiDocumentResponse[T any] struct {
@@ -22,6 +24,27 @@ type PersonalInfoResponse struct {
 	Weight        any    `json:"weight"`
 }
 
+// personalAge fetches the user's age from /personal_info.
+func personalAge() (int, error) {
+	return ageFromPersonalInfo(apiGet("/personal_info", nil))
+}
+
+// ageFromPersonalInfo reads the age from a /personal_info response.
+func ageFromPersonalInfo(body []byte, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	var pi PersonalInfoResponse
+	if err := json.Unmarshal(body, &pi); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+	age, ok := pi.Age.(float64)
+	if !ok || age <= 0 {
+		return 0, fmt.Errorf("no age in personal info")
+	}
+	return int(age), nil
+}
+
 type TagModel struct {
 	ID        string   `json:"id"`
 	Day       string   `json:"day"`
@@ -53,39 +76,58 @@ type SessionModel struct {
 	MotionCount          json.RawMessage `json:"motion_count"`
 }
 
-func printPersonalInfoUsage() {
-	fmt.Print(`Personal info
-
-Usage:
-  oura personal-info [--json|-j]
-  oura personal-info get [--json|-j]
-`)
+type RestModePeriodModel struct {
+	ID        string            `json:"id"`
+	StartDay  string            `json:"start_day"`
+	EndDay    string            `json:"end_day"`
+	StartTime string            `json:"start_time"`
+	EndTime   string            `json:"end_time"`
+	Episodes  []RestModeEpisode `json:"episodes"`
 }
 
-func printTagUsage() {
-	fmt.Print(`Tags
+type RestModeEpisode struct {
+	Tags      []string `json:"tags"`
+	Timestamp string   `json:"timestamp"`
+}
 
-Usage:
-  oura tag [list] [--start-date <date>] [--end-date <date>] [--next-token <token>] [--json|-j]
-  oura tag get <document_id> [--json|-j]
-`)
+type RingConfigurationModel struct {
+	ID              string `json:"id"`
+	Color           string `json:"color"`
+	Design          string `json:"design"`
+	FirmwareVersion string `json:"firmware_version"`
+	HardwareType    string `json:"hardware_type"`
+	SetUpAt         string `json:"set_up_at"`
+	Size            int    `json:"size"`
 }
 
-func printEnhancedTagUsage() {
-	fmt.Print(`Enhanced tags
+// Covers reports whether the rest mode period includes day (YYYY-MM-DD).
+// Periods without an end day are still ongoing.
+func (p RestModePeriodModel) Covers(day string) bool {
+	return p.StartDay <= day && (p.EndDay == "" || p.EndDay >= day)
+}
 
-Usage:
-  oura enhanced-tag [list] [--start-date <date>] [--end-date <date>] [--next-token <token>] [--json|-j]
-  oura enhanced-tag get <document_id> [--json|-j]
-`)
+// Duration returns how long rest mode lasted, measuring ongoing periods
+// up to now.
+func (p RestModePeriodModel) Duration() (time.Duration, bool) {
+	start, err := time.Parse(time.RFC3339, p.StartTime)
+	if err != nil {
+		return 0, false
+	}
+	end := time.Now()
+	if p.EndTime != "" {
+		if end, err = time.Parse(time.RFC3339, p.EndTime); err != nil {
+			return 0, false
+		}
+	}
+	return end.Sub(start), true
 }
 
-func printSessionUsage() {
-	fmt.Print(`Sessions
+func printPersonalInfoUsage() {
+	fmt.Print(`Personal info
 
 Usage:
-  oura session [list] [--start-date <date>] [--end-date <date>] [--next-token <token>] [--json|-j]
-  oura session get <document_id> [--json|-j]
+  oura personal-info [--json|-j]
+  oura personal-info get [--json|-j]
 `)
 }
 
@@ -108,7 +150,13 @@ func handlePersonalInfo(args []string, opts Options) {
 		exitErr(err)
 	}
 
-	if opts.JSON {
+	if opts.JSON && outputFormat == "" && !templateOutput() {
+		if filteredOutput() {
+			checkFilterFor[PersonalInfoResponse]()
+			if body, err = filterBody(endpointModels["/personal_info"], body, true, "", ""); err != nil {
+				exitErr(err)
+			}
+		}
 		writeJSON(body)
 		return
 	}
@@ -117,6 +165,18 @@ func handlePersonalInfo(args []string, opts Options) {
 	if err := json.Unmarshal(body, &pi); err != nil {
 		exitErr(fmt.Errorf("failed to parse response: %w", err))
 	}
+	if opts.JSON && ndjsonOutput() {
+		writeNDJSON("personal_info", []PersonalInfoResponse{pi})
+		return
+	}
+	if opts.JSON && templateOutput() {
+		renderTemplate("personal_info", pi)
+		return
+	}
+	if opts.JSON {
+		writeRecords([]PersonalInfoResponse{pi})
+		return
+	}
 
 	fmt.Println("Personal info")
 	fmt.Println(strings.Repeat("-", 40))
@@ -138,95 +198,136 @@ func handlePersonalInfo(args []string, opts Options) {
 	}
 }
 
-func handleTag(args []string, opts Options) {
-	handleListGet("tag", args, opts)
-}
-
-func handleEnhancedTag(args []string, opts Options) {
-	handleListGet("enhanced_tag", args, opts)
-}
-
-func handleSession(args []string, opts Options) {
-	handleListGet("session", args, opts)
-}
-
-func handleListGet(kind string, args []string, opts Options) {
-	if opts.Help {
-		switch kind {
-		case "tag":
-			printTagUsage()
-		case "enhanced_tag":
-			printEnhancedTagUsage()
-		case "session":
-			printSessionUsage()
-		default:
-			printUsage()
-		}
+func handleRing(args []string, opts Options) {
+	ring := findCollection("ring_configuration")
+	if opts.Help || len(args) == 0 || (args[0] != "current" && args[0] != "firmware") {
+		handleListGet(*ring, args, opts)
 		return
 	}
+	if len(args) != 1 {
+		printCollectionUsage(*ring)
+		os.Exit(1)
+	}
 
-	// Allow: `oura tag --start-date ...` (implicit list).
-	sub := "list"
-	rest := args
-	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
-		sub = args[0]
-		rest = args[1:]
+	body, err := apiGetAll("/ring_configuration", nil)
+	if err != nil {
+		exitErr(err)
+	}
+	var resp MultiDocumentResponse[json.RawMessage]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		exitErr(fmt.Errorf("failed to parse response: %w", err))
+	}
+	rings := make([]RingConfigurationModel, len(resp.Data))
+	for i, raw := range resp.Data {
+		if err := json.Unmarshal(raw, &rings[i]); err != nil {
+			exitErr(fmt.Errorf("failed to parse response: %w", err))
+		}
 	}
+	order := ringsBySetUp(rings)
 
-	switch sub {
-	case "list":
-		params, extra, err := parseRangeQueryFlags(rest)
-		if err != nil {
-			exitErr(err)
+	switch args[0] {
+	case "current":
+		if len(order) == 0 {
+			exitErr(fmt.Errorf("no ring configurations"))
 		}
-		if len(extra) != 0 {
-			exitErr(fmt.Errorf("unexpected args: %s", strings.Join(extra, " ")))
+		latest := order[len(order)-1]
+		if opts.JSON && ndjsonOutput() {
+			writeNDJSON("ring_configuration", []RingConfigurationModel{rings[latest]})
+			return
 		}
-		switch kind {
-		case "tag":
-			listAndPrint[TagModel]("/tag", params, opts, printTagList)
-		case "enhanced_tag":
-			listAndPrint[EnhancedTagModel]("/enhanced_tag", params, opts, printEnhancedTagList)
-		case "session":
-			listAndPrint[SessionModel]("/session", params, opts, printSessionList)
+		if opts.JSON && templateOutput() {
+			renderTemplate("ring_configuration", rings[latest])
+			return
 		}
-	case "get":
-		if len(rest) != 1 {
-			exitErr(fmt.Errorf("missing document_id"))
+		if opts.JSON && tabularOutput() {
+			writeRecords([]RingConfigurationModel{rings[latest]})
+			return
 		}
-		id := rest[0]
-		switch kind {
-		case "tag":
-			getAndPrint[TagModel]("/tag/"+url.PathEscape(id), opts, printTag)
-		case "enhanced_tag":
-			getAndPrint[EnhancedTagModel]("/enhanced_tag/"+url.PathEscape(id), opts, printEnhancedTag)
-		case "session":
-			getAndPrint[SessionModel]("/session/"+url.PathEscape(id), opts, printSession)
+		if opts.JSON {
+			doc := []byte(resp.Data[latest])
+			if filteredOutput() {
+				checkFilterFor[RingConfigurationModel]()
+				if doc, err = filterBody(endpointModels["/ring_configuration"], doc, true, "", ""); err != nil {
+					exitErr(err)
+				}
+			}
+			writeJSON(doc)
+			return
 		}
-	default:
-		switch kind {
-		case "tag":
-			printTagUsage()
-		case "enhanced_tag":
-			printEnhancedTagUsage()
-		case "session":
-			printSessionUsage()
+		printRing(rings[latest])
+	case "firmware":
+		if opts.JSON && (outputFormat != "" || templateOutput()) {
+			sorted := make([]RingConfigurationModel, 0, len(order))
+			for _, i := range order {
+				sorted = append(sorted, rings[i])
+			}
+			switch {
+			case templateOutput():
+				renderRecords("ring_configuration", sorted)
+			case ndjsonOutput():
+				writeNDJSON("ring_configuration", sorted)
+			default:
+				writeRecords(sorted)
+			}
+			return
 		}
-		os.Exit(1)
+		if opts.JSON {
+			sorted := MultiDocumentResponse[json.RawMessage]{Data: make([]json.RawMessage, 0, len(order))}
+			for _, i := range order {
+				sorted.Data = append(sorted.Data, resp.Data[i])
+			}
+			b, err := json.Marshal(sorted)
+			if err == nil && filteredOutput() {
+				checkFilterFor[RingConfigurationModel]()
+				b, err = filterBody(endpointModels["/ring_configuration"], b, false, "", "")
+			}
+			if err != nil {
+				exitErr(err)
+			}
+			writeJSON(b)
+			return
+		}
+		printRingFirmwareTimeline(rings, order)
 	}
 }
 
+// ringsBySetUp returns indexes into rings ordered by set_up_at, oldest
+// first. Configurations without a set-up time sort first.
+func ringsBySetUp(rings []RingConfigurationModel) []int {
+	order := make([]int, len(rings))
+	for i := range order {
+		order[i] = i
+	}
+	setUp := func(r RingConfigurationModel) time.Time {
+		t, _ := time.Parse(time.RFC3339, r.SetUpAt)
+		return t
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return setUp(rings[order[a]]).Before(setUp(rings[order[b]]))
+	})
+	return order
+}
+
 func parseRangeQueryFlags(args []string) (params url.Values, rest []string, err error) {
 	flags, pos, err := parseLongFlags(args)
 	if err != nil {
 		return nil, nil, err
 	}
 	params = url.Values{}
+	now := time.Now()
 	if v := firstFlag(flags, "start-date", "start_date"); v != "" {
-		params.Set("start_date", v)
+		start, _, err := resolveDateExpr(v, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.Set("start_date", start)
 	}
 	if v := firstFlag(flags, "end-date", "end_date"); v != "" {
-		params.Set("end_date", v)
+		_, end, err := resolveDateExpr(v, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.Set("end_date", end)
 	}
 	if v := firstFlag(flags, "next-token", "next_token"); v != "" {
 		params.Set("next_token", v)
@@ -235,11 +336,23 @@ func parseRangeQueryFlags(args []string) (params url.Values, rest []string, err
 }
 
 func listAndPrint[T any](endpoint string, params url.Values, opts Options, printer func(MultiDocumentResponse[T])) {
-	body, err := apiGet(endpoint, params)
+	checkFilterFor[T]()
+	if opts.JSON && ndjsonOutput() {
+		if err := streamRecords[T](endpoint, params); err != nil {
+			exitErr(err)
+		}
+		return
+	}
+	body, err := apiGetAll(endpoint, params)
 	if err != nil {
 		exitErr(err)
 	}
-	if opts.JSON {
+	if opts.JSON && !tabularOutput() && !templateOutput() {
+		if filteredOutput() {
+			if body, err = filterBody(modelFor[T](), body, false, "", ""); err != nil {
+				exitErr(err)
+			}
+		}
 		writeJSON(body)
 		return
 	}
@@ -247,15 +360,33 @@ func listAndPrint[T any](endpoint string, params url.Values, opts Options, print
 	if err := json.Unmarshal(body, &resp); err != nil {
 		exitErr(fmt.Errorf("failed to parse response: %w", err))
 	}
+	if opts.JSON && templateOutput() {
+		collection, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "/")
+		renderRecords(collection, resp.Data)
+		return
+	}
+	if opts.JSON {
+		writeRecords(resp.Data)
+		if resp.NextToken != "" {
+			fmt.Fprintf(os.Stderr, "next_token: %s\n", resp.NextToken)
+		}
+		return
+	}
 	printer(resp)
 }
 
 func getAndPrint[T any](endpoint string, opts Options, printer func(T)) {
+	checkFilterFor[T]()
 	body, err := apiGet(endpoint, nil)
 	if err != nil {
 		exitErr(err)
 	}
-	if opts.JSON {
+	if opts.JSON && outputFormat == "" && !templateOutput() {
+		if filteredOutput() {
+			if body, err = filterBody(modelFor[T](), body, true, "", ""); err != nil {
+				exitErr(err)
+			}
+		}
 		writeJSON(body)
 		return
 	}
@@ -263,6 +394,20 @@ func getAndPrint[T any](endpoint string, opts Options, printer func(T)) {
 	if err := json.Unmarshal(body, &doc); err != nil {
 		exitErr(fmt.Errorf("failed to parse response: %w", err))
 	}
+	if opts.JSON && ndjsonOutput() {
+		collection, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "/")
+		writeNDJSON(collection, []T{doc})
+		return
+	}
+	if opts.JSON && templateOutput() {
+		collection, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "/")
+		renderTemplate(collection, doc)
+		return
+	}
+	if opts.JSON {
+		writeRecords([]T{doc})
+		return
+	}
 	printer(doc)
 }
 
@@ -392,6 +537,124 @@ func printSession(s SessionModel) {
 	}
 }
 
+func printRestModeList(resp MultiDocumentResponse[RestModePeriodModel]) {
+	if len(resp.Data) == 0 {
+		fmt.Println("No rest mode periods")
+		return
+	}
+	fmt.Printf("Rest mode periods (%d)\n", len(resp.Data))
+	fmt.Println(strings.Repeat("-", 72))
+	for _, p := range resp.Data {
+		days := p.StartDay + "..ongoing"
+		if p.EndDay != "" {
+			days = p.StartDay + ".." + p.EndDay
+		}
+		dur := ""
+		if d, ok := p.Duration(); ok {
+			dur = formatDuration(int(d.Seconds()))
+		}
+		fmt.Printf("%s  %s  %s  %d episodes\n", p.ID, days, dur, len(p.Episodes))
+	}
+	printNextToken(resp.NextToken)
+}
+
+func printRestMode(p RestModePeriodModel) {
+	fmt.Println("Rest mode period")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("ID:        %s\n", p.ID)
+	fmt.Printf("Start day: %s\n", p.StartDay)
+	if p.EndDay != "" {
+		fmt.Printf("End day:   %s\n", p.EndDay)
+	} else {
+		fmt.Println("End day:   ongoing")
+	}
+	if p.StartTime != "" {
+		fmt.Printf("Start:     %s\n", p.StartTime)
+	}
+	if p.EndTime != "" {
+		fmt.Printf("End:       %s\n", p.EndTime)
+	}
+	if d, ok := p.Duration(); ok {
+		fmt.Printf("Duration:  %s\n", formatDuration(int(d.Seconds())))
+	}
+	if len(p.Episodes) > 0 {
+		fmt.Println("Episodes:")
+		for _, e := range p.Episodes {
+			fmt.Printf("  %s  %s\n", e.Timestamp, strings.Join(e.Tags, ", "))
+		}
+	}
+}
+
+func printRingList(resp MultiDocumentResponse[RingConfigurationModel]) {
+	if len(resp.Data) == 0 {
+		fmt.Println("No ring configurations")
+		return
+	}
+	fmt.Printf("Ring configurations (%d)\n", len(resp.Data))
+	fmt.Println(strings.Repeat("-", 72))
+	for _, r := range resp.Data {
+		fmt.Printf("%s  %s  %s  fw %s\n", r.ID, firstNonEmpty(r.SetUpAt, "-"), ringLabel(r), firstNonEmpty(r.FirmwareVersion, "?"))
+	}
+	printNextToken(resp.NextToken)
+}
+
+func printRing(r RingConfigurationModel) {
+	fmt.Println("Ring configuration")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("ID:        %s\n", r.ID)
+	if r.HardwareType != "" {
+		fmt.Printf("Hardware:  %s\n", r.HardwareType)
+	}
+	if r.Design != "" {
+		fmt.Printf("Design:    %s\n", r.Design)
+	}
+	if r.Color != "" {
+		fmt.Printf("Color:     %s\n", r.Color)
+	}
+	if r.Size != 0 {
+		fmt.Printf("Size:      %d\n", r.Size)
+	}
+	if r.FirmwareVersion != "" {
+		fmt.Printf("Firmware:  %s\n", r.FirmwareVersion)
+	}
+	if r.SetUpAt != "" {
+		fmt.Printf("Set up at: %s\n", r.SetUpAt)
+	}
+}
+
+func printRingFirmwareTimeline(rings []RingConfigurationModel, order []int) {
+	if len(order) == 0 {
+		fmt.Println("No ring configurations")
+		return
+	}
+	fmt.Println("Firmware timeline")
+	fmt.Println(strings.Repeat("-", 72))
+	prev := ""
+	for _, i := range order {
+		r := rings[i]
+		fw := firstNonEmpty(r.FirmwareVersion, "?")
+		change := ""
+		if prev != "" && fw != prev {
+			change = fmt.Sprintf("  (was %s)", prev)
+		}
+		fmt.Printf("%-25s  fw %-12s %s%s\n", firstNonEmpty(r.SetUpAt, "-"), fw, ringLabel(r), change)
+		prev = fw
+	}
+}
+
+func ringLabel(r RingConfigurationModel) string {
+	var parts []string
+	for _, p := range []string{r.HardwareType, r.Design, r.Color} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if r.Size != 0 {
+		parts = append(parts, fmt.Sprintf("size %d", r.Size))
+	}
+	return strings.Join(parts, " ")
+}
+
 func firstNonEmpty(v ...string) string {
 	for _, s := range v {
 		if s != "" {
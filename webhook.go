@@ -1,18 +1,15 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
 )
 
-const webhookBase = "https://api.ouraring.com/v2/webhook"
-
 type WebhookSubscription struct {
 	ID             string `json:"id"`
 	CallbackURL    string `json:"callback_url"`
@@ -157,12 +154,7 @@ func webhookList(opts Options) error {
 	}
 
 	if opts.JSON {
-		// Preserve the original API payload.
-		os.Stdout.Write(body)
-		if len(body) == 0 || body[len(body)-1] != '\n' {
-			fmt.Println()
-		}
-		return nil
+		return writeSubscriptionOutput(body, false)
 	}
 
 	var subs []WebhookSubscription
@@ -184,6 +176,54 @@ func webhookList(opts Options) error {
 	return nil
 }
 
+// writeSubscriptionOutput prints a --json response: the API payload as is,
+// or through writeSubscriptionRecords when a format, template or filter is
+// set.
+func writeSubscriptionOutput(body []byte, single bool) error {
+	if outputFormat != "" || templateOutput() || filteredOutput() {
+		return writeSubscriptionRecords(body, single)
+	}
+	os.Stdout.Write(body)
+	if len(body) == 0 || body[len(body)-1] != '\n' {
+		fmt.Println()
+	}
+	return nil
+}
+
+// writeSubscriptionRecords prints a subscription list (or one subscription)
+// as CSV, TSV, NDJSON, through --template, or as JSON with --fields/--where.
+func writeSubscriptionRecords(body []byte, single bool) error {
+	var subs []WebhookSubscription
+	if single {
+		subs = make([]WebhookSubscription, 1)
+		if err := json.Unmarshal(body, &subs[0]); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	} else if err := json.Unmarshal(body, &subs); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	switch {
+	case outputFormat == "" && !templateOutput():
+		// --json with --fields/--where.
+		out := filterValues(subs)
+		if single {
+			if len(out) == 0 {
+				return nil
+			}
+			writeJSONToStdout(out[0])
+			return nil
+		}
+		writeJSONToStdout(out)
+	case templateOutput():
+		renderRecords("webhook_subscription", subs)
+	case ndjsonOutput():
+		writeNDJSON("webhook_subscription", subs)
+	default:
+		writeRecords(subs)
+	}
+	return nil
+}
+
 func webhookGet(id string, opts Options) error {
 	body, _, err := webhookDo("GET", "/subscription/"+urlPathEscape(id), nil)
 	if err != nil {
@@ -191,11 +231,7 @@ func webhookGet(id string, opts Options) error {
 	}
 
 	if opts.JSON {
-		os.Stdout.Write(body)
-		if len(body) == 0 || body[len(body)-1] != '\n' {
-			fmt.Println()
-		}
-		return nil
+		return writeSubscriptionOutput(body, true)
 	}
 
 	var s WebhookSubscription
@@ -250,11 +286,7 @@ func webhookCreate(args []string, opts Options) error {
 	}
 
 	if opts.JSON {
-		os.Stdout.Write(body)
-		if len(body) == 0 || body[len(body)-1] != '\n' {
-			fmt.Println()
-		}
-		return nil
+		return writeSubscriptionOutput(body, true)
 	}
 
 	var s WebhookSubscription
@@ -317,11 +349,7 @@ func webhookUpdate(id string, args []string, opts Options) error {
 	}
 
 	if opts.JSON {
-		os.Stdout.Write(body)
-		if len(body) == 0 || body[len(body)-1] != '\n' {
-			fmt.Println()
-		}
-		return nil
+		return writeSubscriptionOutput(body, true)
 	}
 
 	var s WebhookSubscription
@@ -357,11 +385,7 @@ func webhookRenew(id string, opts Options) error {
 	}
 
 	if opts.JSON {
-		os.Stdout.Write(body)
-		if len(body) == 0 || body[len(body)-1] != '\n' {
-			fmt.Println()
-		}
-		return nil
+		return writeSubscriptionOutput(body, true)
 	}
 
 	var s WebhookSubscription
@@ -379,39 +403,26 @@ func webhookRenew(id string, opts Options) error {
 }
 
 func webhookDo(method string, endpoint string, payload any) (respBody []byte, status int, err error) {
-	var body io.Reader
+	var body []byte
 	if payload != nil {
-		b, err := json.Marshal(payload)
+		body, err = json.Marshal(payload)
 		if err != nil {
 			return nil, 0, err
 		}
-		body = bytes.NewReader(b)
-	}
-
-	req, err := http.NewRequest(method, webhookBase+endpoint, body)
-	if err != nil {
-		return nil, 0, err
 	}
 
 	// Webhook subscription endpoints use app credentials.
-	req.Header.Set("x-client-id", config.ClientID)
-	req.Header.Set("x-client-secret", config.ClientSecret)
+	header := http.Header{}
+	header.Set("x-client-id", config.ClientID)
+	header.Set("x-client-secret", config.ClientSecret)
 	if payload != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, 0, err
+		header.Set("Content-Type", "application/json")
 	}
-	defer resp.Body.Close()
 
-	respBody, err = io.ReadAll(resp.Body)
+	respBody, status, err = sendRequest(context.Background(), method, webhookBase+endpoint, body, header)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	status = resp.StatusCode
 	if status < 200 || status > 299 {
 		return nil, status, fmt.Errorf("API error %d: %s", status, strings.TrimSpace(string(respBody)))
 	}
@@ -446,6 +457,28 @@ func parseLongFlags(args []string) (flags map[string]string, pos []string, err e
 	return flags, pos, nil
 }
 
+// cutBoolFlag removes value-less --name switches from args, reporting
+// whether any of them was present. It must run before parseLongFlags, which
+// expects every flag to carry a value.
+func cutBoolFlag(args []string, names ...string) (rest []string, found bool) {
+	rest = make([]string, 0, len(args))
+	for _, a := range args {
+		matched := false
+		for _, n := range names {
+			if a == "--"+n {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			found = true
+			continue
+		}
+		rest = append(rest, a)
+	}
+	return rest, found
+}
+
 func firstFlag(flags map[string]string, keys ...string) string {
 	for _, k := range keys {
 		if v, ok := flags[k]; ok {